@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/config"
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// modeSpecificFlags lists, per mode, the typing test flags that do not apply
+// to it.
+var modeSpecificFlags = map[models.Mode][]string{
+	models.QuoteMode:  append([]string{"duration", "word-count", "include-punctuation", "include-numbers"}, customFlags...),
+	models.WordsMode:  append(append([]string{"duration"}, customFlags...), quoteFlags...),
+	models.TimeMode:   append(append([]string{"word-count"}, customFlags...), quoteFlags...),
+	models.CustomMode: append([]string{"duration", "word-count", "include-punctuation", "include-numbers"}, quoteFlags...),
+	models.ZenMode:    append(append([]string{"duration", "word-count", "include-punctuation", "include-numbers", "difficulty", "stop-on-error"}, customFlags...), quoteFlags...),
+}
+
+// customFlags only apply to custom mode.
+var customFlags = []string{"file", "passage-words", "chunk-words", "collapse-whitespace"}
+
+// quoteFlags only apply to quote mode.
+var quoteFlags = []string{"quote-length", "quote-id", "source-file", "go-module", "go-package", "snippet", "snippet-lines", "snippet-min-lines"}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect configuration defaults",
+	Long: `Typing test flags can be given defaults in a YAML config file and with
+TYPING_TEST_* environment variables. Flags passed on the command line win over
+a preset, which wins over environment variables, then the config file, then
+the built-in defaults.`,
+	Args: cobra.NoArgs,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:     "show",
+	Short:   "Print the effective configuration and where each value came from",
+	Example: "typing-test-tui config show\nTYPING_TEST_MODE=time typing-test-tui config show\ntyping-test-tui config show --preset go-code-60s-punct",
+	Args:    cobra.NoArgs,
+	Run:     showConfig,
+}
+
+// settingsSkip lists flags that configure the app itself rather than a
+// typing test, so they cannot come from a config file or preset.
+var settingsSkip = []string{"help", "config", "preset"}
+
+// loadSettings fills the typing test flags that were not passed on the
+// command line from the named preset, the environment and the config file
+// named by cmd.
+func loadSettings(cmd *cobra.Command, flags *pflag.FlagSet, presetName string) (config.File, map[string]config.Origin, error) {
+	path, _ := cmd.Flags().GetString("config")
+	file, err := config.Load(path, os.LookupEnv)
+	if err != nil {
+		return file, nil, err
+	}
+
+	var preset config.Preset
+	if presetName != "" {
+		presets, err := config.DefaultPresets()
+		if err != nil {
+			return file, nil, err
+		}
+		if preset, err = presets.Get(presetName); err != nil {
+			return file, nil, err
+		}
+	}
+
+	origins, err := config.Apply(flags, preset, file, os.LookupEnv, settingsSkip...)
+	return file, origins, err
+}
+
+// quoteChoiceFlags choose which quotes to type, so a quote id passed on the
+// command line replaces them.
+var quoteChoiceFlags = []string{"quote-length", "source-file", "go-module", "go-package"}
+
+// relaxModeSpecific resets settings from a preset, the environment or the
+// config file that do not apply to mode, so a saved duration does not stop a
+// quote test from starting. A quote id passed on the command line likewise
+// resets the saved quote lengths and sources. Flags passed on the command line
+// are still validated.
+func relaxModeSpecific(flags *pflag.FlagSet, mode models.Mode, origins map[string]config.Origin) {
+	relaxInherited(flags, modeSpecificFlags[mode], origins)
+	if origins["quote-id"].Source == config.SourceFlag {
+		relaxInherited(flags, quoteChoiceFlags, origins)
+	}
+}
+
+// relaxInherited resets the named flags whose values came from a preset, the
+// environment or the config file.
+func relaxInherited(flags *pflag.FlagSet, names []string, origins map[string]config.Origin) {
+	for _, name := range names {
+		switch origins[name].Source {
+		case config.SourcePreset, config.SourceEnv, config.SourceFile:
+			flag := flags.Lookup(name)
+			if flag == nil {
+				continue
+			}
+			if err := resetFlag(flag); err == nil {
+				origins[name] = config.Origin{Source: config.SourceDefault}
+			}
+		}
+	}
+}
+
+// resetFlag restores the default value of flag. List flags are emptied, as
+// setting them appends once they hold a value.
+func resetFlag(flag *pflag.Flag) error {
+	if slice, ok := flag.Value.(pflag.SliceValue); ok {
+		return slice.Replace(nil)
+	}
+	return flag.Value.Set(flag.DefValue)
+}
+
+func showConfig(cmd *cobra.Command, _ []string) {
+	flags := rootCmd.Flags()
+	preset, _ := cmd.Flags().GetString("preset")
+	file, origins, err := loadSettings(cmd, flags, preset)
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+	if mode, err := flags.GetString("mode"); err == nil {
+		relaxModeSpecific(flags, models.Mode(mode), origins)
+	}
+
+	out := cmd.OutOrStdout()
+	status := ""
+	if !file.Found {
+		status = " (not found)"
+	}
+	fmt.Fprintf(out, "Config file: %s%s\n\n", file.Path, status)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Setting\tValue\tSource")
+	flags.VisitAll(func(flag *pflag.Flag) {
+		origin, ok := origins[flag.Name]
+		if !ok {
+			return
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", flag.Name, flag.Value.String(), origin)
+	})
+	w.Flush()
+}
+
+func init() {
+	configShowCmd.Flags().String("preset", "", "Include the values of this saved preset")
+	configCmd.AddCommand(configShowCmd)
+	rootCmd.AddCommand(configCmd)
+}
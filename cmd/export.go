@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/history"
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export saved results as CSV or JSON",
+	Long: `Write the full result records from your history, oldest first, for use in
+spreadsheets and notebooks. Column and key names are the same in every format
+and stay stable between releases.`,
+	Example: `typing-test-tui export > results.csv
+typing-test-tui export --output results.json
+typing-test-tui export --format ndjson --mode time --since 2024-01-01`,
+	Args: cobra.NoArgs,
+	Run:  exportHistory,
+}
+
+func exportHistory(cmd *cobra.Command, _ []string) {
+	filter, err := historyFilterFromFlags(cmd)
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+
+	output, _ := cmd.Flags().GetString("output")
+	value, _ := cmd.Flags().GetString("format")
+	if !cmd.Flags().Changed("format") && output != "" {
+		// Let the file extension pick the format, e.g. results.json.
+		if ext := strings.TrimPrefix(filepath.Ext(output), "."); ext != "" {
+			if _, err := history.ParseFormat(ext); err == nil {
+				value = ext
+			}
+		}
+	}
+	format, err := history.ParseFormat(value)
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+
+	store, err := history.Default()
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+	all, err := store.Load()
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+
+	results := make([]models.Result, 0, len(all))
+	for _, result := range all {
+		if filter.Match(result) {
+			results = append(results, result)
+		}
+	}
+	// Imports are appended to the file, so file order is not date order.
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].FinishedAt.Before(results[j].FinishedAt)
+	})
+
+	if output == "" {
+		if err := history.Export(cmd.OutOrStdout(), results, format); err != nil {
+			cmd.Println("Error:", err)
+		}
+		return
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+	if err := history.Export(file, results, format); err != nil {
+		file.Close()
+		cmd.Println("Error:", err)
+		return
+	}
+	if err := file.Close(); err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+	cmd.Printf("Exported %d results to %s\n", len(results), output)
+}
+
+func init() {
+	addHistoryFilterFlags(exportCmd)
+	exportCmd.Flags().StringP("format", "f", string(history.FormatCSV), "Output format ('csv', 'json', 'ndjson'); inferred from --output when omitted")
+	exportCmd.Flags().StringP("output", "o", "", "Write to this file instead of standard output")
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-isatty"
+	"github.com/neilsmahajan/typing-test-tui/internal/history"
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+	"github.com/neilsmahajan/typing-test-tui/internal/ui/history_view"
+	"github.com/spf13/cobra"
+)
+
+const (
+	historyDateLayout = "2006-01-02"
+	// tableFormat is the human-readable default of history --format.
+	tableFormat = "table"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Browse saved results",
+	Long: `List the results of previous typing tests, newest first by default.
+When run in a terminal the results open in an interactive table; when the
+output is piped they are printed as plain text.`,
+	Example: `typing-test-tui history
+typing-test-tui history --mode time --sort wpm --limit 10
+typing-test-tui history --since 2024-01-01 --until 2024-01-31 --plain
+typing-test-tui history --mode words --format csv`,
+	Args: cobra.NoArgs,
+	Run:  showHistory,
+}
+
+func showHistory(cmd *cobra.Command, _ []string) {
+	filter, err := historyFilterFromFlags(cmd)
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+
+	sortValue, _ := cmd.Flags().GetString("sort")
+	field, err := history.ParseSortField(sortValue)
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit < 0 {
+		cmd.Println("Error: limit must not be negative")
+		return
+	}
+
+	var format history.Format
+	if value, _ := cmd.Flags().GetString("format"); value != tableFormat {
+		if format, err = history.ParseFormat(value); err != nil {
+			cmd.Println("Error:", err)
+			return
+		}
+	}
+
+	store, err := history.Default()
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+	all, err := store.Load()
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+	results := history.Query(all, filter, field, limit)
+
+	if format != "" {
+		if err := history.Export(cmd.OutOrStdout(), results, format); err != nil {
+			cmd.Println("Error:", err)
+		}
+		return
+	}
+
+	plain, _ := cmd.Flags().GetBool("plain")
+	if len(results) == 0 || plain || !isTerminal(cmd.OutOrStdout()) {
+		printHistory(cmd.OutOrStdout(), results)
+		return
+	}
+
+	model := history_view.InitialModel("Typing Test History", results)
+	if _, err := tea.NewProgram(model, tea.WithAltScreen()).Run(); err != nil {
+		cmd.Println("Error running history view:", err)
+	}
+}
+
+func historyFilterFromFlags(cmd *cobra.Command) (history.Filter, error) {
+	var filter history.Filter
+	flags := cmd.Flags()
+
+	if mode, _ := flags.GetString("mode"); mode != "" {
+		filter.Mode = models.Mode(strings.ToLower(strings.TrimSpace(mode)))
+		switch filter.Mode {
+		case models.QuoteMode, models.WordsMode, models.TimeMode, models.CustomMode, models.ZenMode:
+		default:
+			return filter, fmt.Errorf("unsupported mode %q. Supported modes: 'quote', 'words', 'time', 'custom', 'zen'", mode)
+		}
+	}
+
+	if language, _ := flags.GetString("language"); language != "" {
+		lang, err := normalizeLanguage(language)
+		if err != nil {
+			return filter, err
+		}
+		filter.Language = lang
+	}
+
+	if since, _ := flags.GetString("since"); since != "" {
+		day, err := time.ParseInLocation(historyDateLayout, since, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("since must be a date in YYYY-MM-DD format, got %q", since)
+		}
+		filter.Since = day
+	}
+
+	if until, _ := flags.GetString("until"); until != "" {
+		day, err := time.ParseInLocation(historyDateLayout, until, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("until must be a date in YYYY-MM-DD format, got %q", until)
+		}
+		// The end date is inclusive, so stop at the start of the next day.
+		filter.Until = day.AddDate(0, 0, 1)
+	}
+
+	if flags.Changed("include-punctuation") {
+		punctuation, _ := flags.GetBool("include-punctuation")
+		filter.Punctuation = &punctuation
+	}
+	if flags.Changed("include-numbers") {
+		numbers, _ := flags.GetBool("include-numbers")
+		filter.Numbers = &numbers
+	}
+
+	if quoteID, _ := flags.GetInt("quote-id"); quoteID != 0 {
+		if quoteID < 0 {
+			return filter, fmt.Errorf("quote-id must be positive, got %d", quoteID)
+		}
+		filter.QuoteID = quoteID
+	}
+
+	return filter, nil
+}
+
+func printHistory(out io.Writer, results []models.Result) {
+	if len(results) == 0 {
+		fmt.Fprintln(out, "No results found.")
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(history.Headers, "\t"))
+	for _, result := range results {
+		fmt.Fprintln(w, strings.Join(history.Row(result), "\t"))
+	}
+	w.Flush()
+}
+
+func isTerminal(out io.Writer) bool {
+	file, ok := out.(*os.File)
+	return ok && isatty.IsTerminal(file.Fd())
+}
+
+// addHistoryFilterFlags registers the flags read by historyFilterFromFlags.
+func addHistoryFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("mode", "m", "", "Only include results for this mode ('quote', 'words', 'time', 'custom', 'zen')")
+	cmd.Flags().StringP("language", "l", "", "Only include results for this language")
+	cmd.Flags().String("since", "", "Only include results finished on or after this date (YYYY-MM-DD)")
+	cmd.Flags().String("until", "", "Only include results finished on or before this date (YYYY-MM-DD)")
+	cmd.Flags().BoolP("include-punctuation", "p", false, "Only include results with (or, with =false, without) punctuation")
+	cmd.Flags().BoolP("include-numbers", "n", false, "Only include results with (or, with =false, without) numbers")
+	cmd.Flags().Int("quote-id", 0, "Only include results for this quote (combine with --language, as ids are per language)")
+}
+
+func init() {
+	addHistoryFilterFlags(historyCmd)
+	historyCmd.Flags().String("sort", string(history.SortByDate), "Sort results by 'date', 'wpm' or 'accuracy' (best or newest first)")
+	historyCmd.Flags().Int("limit", 0, "Maximum number of results to show (0 shows all)")
+	historyCmd.Flags().String("format", tableFormat, "Output format ('table', 'csv', 'json', 'ndjson')")
+	historyCmd.Flags().Bool("plain", false, "Print plain text even when running in a terminal")
+	rootCmd.AddCommand(historyCmd)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/history"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import results from other typing sites",
+	Long:  `Import results recorded elsewhere into your history so personal bests, stats and trends include them.`,
+	Args:  cobra.NoArgs,
+}
+
+var importMonkeytypeCmd = &cobra.Command{
+	Use:   "monkeytype <file.csv>",
+	Short: "Import a monkeytype results CSV export",
+	Long: `Import the CSV file downloaded from monkeytype's account page. Time, words and
+quote tests in supported languages are added to your history; other rows are
+skipped. Importing the same file again only adds results that are new.`,
+	Example: "typing-test-tui import monkeytype results.csv",
+	Args:    cobra.ExactArgs(1),
+	Run:     importMonkeytype,
+}
+
+func importMonkeytype(cmd *cobra.Command, args []string) {
+	file, err := os.Open(args[0])
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+	imported, report, err := history.ParseMonkeytype(file)
+	file.Close()
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+
+	store, err := history.Default()
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+	fresh, duplicates, err := store.Import(imported)
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+
+	cmd.Printf("Imported %d results (%d already imported, %d skipped).\n", len(fresh), duplicates, len(report.Skipped))
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	if verbose {
+		for _, reason := range report.Skipped {
+			cmd.Println(" -", reason)
+		}
+	}
+}
+
+func init() {
+	importMonkeytypeCmd.Flags().BoolP("verbose", "v", false, "List the rows that were skipped and why")
+	importCmd.AddCommand(importMonkeytypeCmd)
+	rootCmd.AddCommand(importCmd)
+}
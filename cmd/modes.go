@@ -14,9 +14,11 @@ var modesCmd = &cobra.Command{
 
 func listModes(cmd *cobra.Command, args []string) {
 	cmd.Println("Supported Modes:")
-	cmd.Println(" - quote : Type predefined quotes.")
-	cmd.Println(" - words : Type a set number of random words.")
-	cmd.Println(" - time  : Type as many words as you can in a set time limit.")
+	cmd.Println(" - quote  : Type predefined quotes.")
+	cmd.Println(" - words  : Type a set number of random words.")
+	cmd.Println(" - time   : Type as many words as you can in a set time limit.")
+	cmd.Println(" - custom : Type your own text from a file or standard input.")
+	cmd.Println(" - zen    : Type freely with no target text until you press Tab.")
 	cmd.Println("\nYou can specify a mode using the --mode or -m flag when starting a typing test.")
 }
 
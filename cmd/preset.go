@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var presetCmd = &cobra.Command{
+	Use:   "preset",
+	Short: "Save and run named test configurations",
+	Long: `Presets bundle a full typing test configuration under a name so the same
+test can be repeated later, e.g. for weekly comparisons.`,
+	Args: cobra.NoArgs,
+}
+
+var presetSaveCmd = &cobra.Command{
+	Use:   "save <name> [flags]",
+	Short: "Save the given test flags as a preset",
+	Long: `Save a preset from the same flags a typing test accepts. Settings that are
+not passed are filled in from the environment, the config file and the
+built-in defaults, so the preset records a complete configuration. Saving
+under an existing name replaces that preset.`,
+	Example: "typing-test-tui preset save go-code-60s-punct --mode time --language go --duration 60 --include-punctuation",
+	Args:    cobra.ExactArgs(1),
+	Run:     savePreset,
+}
+
+var presetRunCmd = &cobra.Command{
+	Use:     "run <name>",
+	Short:   "Start a typing test from a preset",
+	Long:    `Start a typing test from a preset. This is the same as 'typing-test-tui --preset <name>'.`,
+	Example: "typing-test-tui preset run go-code-60s-punct",
+	Args:    cobra.ExactArgs(1),
+	Run:     runPreset,
+}
+
+var presetListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List saved presets",
+	Args:    cobra.NoArgs,
+	Run:     listPresets,
+}
+
+func savePreset(cmd *cobra.Command, args []string) {
+	name := args[0]
+	if err := config.ValidatePresetName(name); err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+
+	flags := cmd.Flags()
+	_, origins, err := loadSettings(cmd, flags, "")
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+	cfg, err := configFromFlags(flags, origins)
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+
+	values := presetValues(flags)
+	// Store the canonical language name and leave out settings the mode
+	// does not use, so presets read the same however they were typed.
+	values["language"] = string(cfg.Language)
+	for _, name := range modeSpecificFlags[cfg.Mode] {
+		delete(values, name)
+	}
+	preset := config.Preset{Name: name, Values: values}
+	presets, err := config.DefaultPresets()
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+	replaced, err := presets.Save(preset)
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+
+	verb := "Saved"
+	if replaced {
+		verb = "Updated"
+	}
+	cmd.Printf("%s preset %q: %s\n", verb, name, describePreset(preset))
+}
+
+// presetValues collects the typing test settings in flags.
+func presetValues(flags *pflag.FlagSet) map[string]string {
+	skipped := make(map[string]bool, len(settingsSkip))
+	for _, name := range settingsSkip {
+		skipped[name] = true
+	}
+
+	values := make(map[string]string)
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if skipped[flag.Name] {
+			return
+		}
+		// List flags print as "[a,b]" but are set from "a,b".
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			values[flag.Name] = strings.Join(slice.GetSlice(), ",")
+			return
+		}
+		values[flag.Name] = flag.Value.String()
+	})
+	return values
+}
+
+func runPreset(cmd *cobra.Command, args []string) {
+	startTypingTest(cmd, rootCmd.Flags(), args[0])
+}
+
+func listPresets(cmd *cobra.Command, _ []string) {
+	presets, err := config.DefaultPresets()
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+	saved, err := presets.List()
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+
+	out := cmd.OutOrStdout()
+	if len(saved) == 0 {
+		fmt.Fprintln(out, "No presets saved yet. Create one with 'typing-test-tui preset save <name> [flags]'.")
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Name\tSettings")
+	for _, preset := range saved {
+		fmt.Fprintf(w, "%s\t%s\n", preset.Name, describePreset(preset))
+	}
+	w.Flush()
+}
+
+// describePreset lists a preset's values as key=value pairs, mode first.
+func describePreset(preset config.Preset) string {
+	keys := make([]string, 0, len(preset.Values))
+	for key := range preset.Values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if (keys[i] == "mode") != (keys[j] == "mode") {
+			return keys[i] == "mode"
+		}
+		return keys[i] < keys[j]
+	})
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key + "=" + preset.Values[key]
+	}
+	return strings.Join(parts, " ")
+}
+
+func init() {
+	addTypingFlags(presetSaveCmd.Flags())
+	presetCmd.AddCommand(presetSaveCmd, presetRunCmd, presetListCmd)
+	rootCmd.AddCommand(presetCmd)
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/neilsmahajan/typing-test-tui/internal/loaders"
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+	"github.com/neilsmahajan/typing-test-tui/internal/quotesearch"
+	"github.com/neilsmahajan/typing-test-tui/internal/ui/quote_picker"
+	"github.com/neilsmahajan/typing-test-tui/internal/ui/typing"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// quoteExcerptWidth caps the quote column of the plain search output.
+const quoteExcerptWidth = 60
+
+var quotesCmd = &cobra.Command{
+	Use:   "quotes",
+	Short: "Find quotes to type",
+	Args:  cobra.NoArgs,
+}
+
+var quotesSearchCmd = &cobra.Command{
+	Use:   "search [query...]",
+	Short: "Search quotes by text or source",
+	Long: `Search the quotes of a language by words from their text or source. Quotes
+containing the whole query come first, then quotes containing every word of
+it, then quotes matching every word with a typo or two (turn those off with
+--exact). Without a query every quote is listed.
+
+When run in a terminal the matches open in an interactive picker that keeps
+searching as you type; pressing Enter starts quote mode on the selected
+quote. When the output is piped the matches are printed as plain text with
+the ids to pass to --quote-id.`,
+	Example: `typing-test-tui quotes search
+typing-test-tui quotes search glue that binds
+typing-test-tui quotes search tolkien --quote-length long --plain
+typing-test-tui quotes search --language spanish cervantes`,
+	Run: searchQuotes,
+}
+
+func searchQuotes(cmd *cobra.Command, args []string) {
+	flags := cmd.Flags()
+
+	languageValue, _ := flags.GetString("language")
+	language, err := normalizeLanguage(languageValue)
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+
+	lengthValues, _ := flags.GetStringSlice("quote-length")
+	lengths, err := parseQuoteLengths(lengthValues)
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+
+	limit, _ := flags.GetInt("limit")
+	if limit < 0 {
+		cmd.Println("Error: limit must not be negative")
+		return
+	}
+	exact, _ := flags.GetBool("exact")
+
+	languageQuotes, err := loaders.LoadQuotes(language)
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+	languageQuotes = languageQuotes.WithLengths(lengths)
+
+	query := strings.Join(args, " ")
+	plain, _ := flags.GetBool("plain")
+	if plain || !isTerminal(cmd.OutOrStdout()) {
+		matches := quotesearch.NewIndex(languageQuotes).Search(query, exact, limit)
+		printQuoteMatches(cmd.OutOrStdout(), matches)
+		return
+	}
+
+	model := quote_picker.InitialModel(languageQuotes, quote_picker.Options{
+		Title:    fmt.Sprintf("%s Quotes", typing.DisplayLanguage(language)),
+		Language: language,
+		Query:    query,
+		Exact:    exact,
+		Limit:    limit,
+	})
+	final, err := tea.NewProgram(model, tea.WithAltScreen()).Run()
+	if err != nil {
+		cmd.Println("Error running quote picker:", err)
+		return
+	}
+	quote, ok := final.(quote_picker.Model).Chosen()
+	if !ok {
+		return
+	}
+
+	testFlags := rootCmd.Flags()
+	if err := pickQuote(testFlags, language, quote.ID); err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+	startTypingTest(cmd, testFlags, "")
+}
+
+// pickQuote sets the typing test flags as if the quote with id had been
+// picked on the command line.
+func pickQuote(flags *pflag.FlagSet, language models.Language, id int) error {
+	for name, value := range map[string]string{
+		"mode":     string(models.QuoteMode),
+		"language": string(language),
+		"quote-id": strconv.Itoa(id),
+	} {
+		if err := flags.Set(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printQuoteMatches(out io.Writer, matches []quotesearch.Match) {
+	if len(matches) == 0 {
+		fmt.Fprintln(out, "No quotes found.")
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tLength\tChars\tSource\tQuote")
+	for _, match := range matches {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\n",
+			match.Quote.ID,
+			match.Length,
+			match.Quote.Length,
+			quote_picker.Excerpt(match.Quote.Source, quoteExcerptWidth/2),
+			quote_picker.Excerpt(match.Quote.Text, quoteExcerptWidth),
+		)
+	}
+	w.Flush()
+}
+
+func init() {
+	quotesSearchCmd.Flags().StringP("language", "l", string(models.English), "Language whose quotes to search")
+	quotesSearchCmd.Flags().StringSlice("quote-length", nil, "Only include quotes of these lengths ('short', 'medium', 'long', 'thicc' or 'all'; comma separated or repeated)")
+	quotesSearchCmd.Flags().Bool("exact", false, "Only match the words as typed, without allowing for typos")
+	quotesSearchCmd.Flags().Int("limit", 50, "Maximum number of matches to show (0 shows all)")
+	quotesSearchCmd.Flags().Bool("plain", false, "Print plain text even when running in a terminal")
+	quotesCmd.AddCommand(quotesSearchCmd)
+	rootCmd.AddCommand(quotesCmd)
+}
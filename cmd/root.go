@@ -3,12 +3,15 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/neilsmahajan/typing-test-tui/internal/app"
+	"github.com/neilsmahajan/typing-test-tui/internal/config"
 	"github.com/neilsmahajan/typing-test-tui/internal/models"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -23,6 +26,9 @@ It provides an interactive interface to practice and improve your typing skills.
 const (
 	defaultDuration  = 60
 	defaultWordCount = 50
+
+	defaultSnippetLines    = 15
+	defaultSnippetMinLines = 1
 )
 
 var (
@@ -33,72 +39,258 @@ var (
 )
 
 func runTypingTest(cmd *cobra.Command, _ []string) {
-	mode, err := cmd.Flags().GetString("mode")
+	preset, err := cmd.Flags().GetString("preset")
 	if err != nil {
-		fmt.Println("Error reading mode flag:", err)
+		fmt.Println("Error reading preset flag:", err)
 		return
 	}
+	startTypingTest(cmd, cmd.Flags(), preset)
+}
 
-	language, err := cmd.Flags().GetString("language")
+// startTypingTest runs the typing test described by flags once defaults from
+// the named preset, the environment and the config file are filled in.
+func startTypingTest(cmd *cobra.Command, flags *pflag.FlagSet, preset string) {
+	_, origins, err := loadSettings(cmd, flags, preset)
 	if err != nil {
-		fmt.Println("Error reading language flag:", err)
+		fmt.Println("Error:", err)
 		return
 	}
 
-	duration, err := cmd.Flags().GetInt("duration")
+	cfg, err := configFromFlags(flags, origins)
 	if err != nil {
-		fmt.Println("Error reading duration flag:", err)
+		fmt.Println("Error:", err)
 		return
 	}
 
-	wordCount, err := cmd.Flags().GetInt("word-count")
+	if err := app.Run(cfg); err != nil {
+		fmt.Println("Error running app:", err)
+	}
+}
+
+// configFromFlags validates the typing test flags and turns them into a
+// test configuration. Mode-specific settings that came from defaults rather
+// than the command line are relaxed first (see relaxModeSpecific).
+func configFromFlags(flags *pflag.FlagSet, origins map[string]config.Origin) (models.Config, error) {
+	mode, err := flags.GetString("mode")
 	if err != nil {
-		fmt.Println("Error reading word count flag:", err)
-		return
+		return models.Config{}, fmt.Errorf("reading mode flag: %w", err)
 	}
+	relaxModeSpecific(flags, models.Mode(mode), origins)
 
-	includePunctuation, err := cmd.Flags().GetBool("include-punctuation")
+	language, err := flags.GetString("language")
 	if err != nil {
-		fmt.Println("Error reading punctuation flag:", err)
-		return
+		return models.Config{}, fmt.Errorf("reading language flag: %w", err)
 	}
 
-	includeNumbers, err := cmd.Flags().GetBool("include-numbers")
+	duration, err := flags.GetInt("duration")
 	if err != nil {
-		fmt.Println("Error reading numbers flag:", err)
-		return
+		return models.Config{}, fmt.Errorf("reading duration flag: %w", err)
+	}
+
+	wordCount, err := flags.GetInt("word-count")
+	if err != nil {
+		return models.Config{}, fmt.Errorf("reading word count flag: %w", err)
+	}
+
+	includePunctuation, err := flags.GetBool("include-punctuation")
+	if err != nil {
+		return models.Config{}, fmt.Errorf("reading punctuation flag: %w", err)
+	}
+
+	includeNumbers, err := flags.GetBool("include-numbers")
+	if err != nil {
+		return models.Config{}, fmt.Errorf("reading numbers flag: %w", err)
+	}
+
+	wpmMetric, err := flags.GetString("wpm-metric")
+	if err != nil {
+		return models.Config{}, fmt.Errorf("reading WPM metric flag: %w", err)
+	}
+
+	difficultyValue, err := flags.GetString("difficulty")
+	if err != nil {
+		return models.Config{}, fmt.Errorf("reading difficulty flag: %w", err)
+	}
+
+	stopOnErrorValue, err := flags.GetString("stop-on-error")
+	if err != nil {
+		return models.Config{}, fmt.Errorf("reading stop on error flag: %w", err)
+	}
+
+	confidenceValue, err := flags.GetString("confidence")
+	if err != nil {
+		return models.Config{}, fmt.Errorf("reading confidence flag: %w", err)
+	}
+
+	custom, err := customOptionsFromFlags(flags)
+	if err != nil {
+		return models.Config{}, err
+	}
+
+	source, err := sourceOptionsFromFlags(flags)
+	if err != nil {
+		return models.Config{}, err
+	}
+
+	quoteLengthValues, err := flags.GetStringSlice("quote-length")
+	if err != nil {
+		return models.Config{}, fmt.Errorf("reading quote length flag: %w", err)
+	}
+
+	quoteID, err := flags.GetInt("quote-id")
+	if err != nil {
+		return models.Config{}, fmt.Errorf("reading quote id flag: %w", err)
 	}
 
 	modeValue := models.Mode(mode)
 
 	if err := validateFlags(modeValue, duration, wordCount, includePunctuation, includeNumbers); err != nil {
-		fmt.Println("Error:", err)
-		return
+		return models.Config{}, err
+	}
+
+	if err := validateCustomFlags(modeValue, custom); err != nil {
+		return models.Config{}, err
 	}
 
 	normalizedLanguage, err := normalizeLanguage(language)
 	if err != nil {
-		fmt.Println("Error:", err)
-		return
+		return models.Config{}, err
+	}
+
+	if err := validateSourceFlags(modeValue, normalizedLanguage, source); err != nil {
+		return models.Config{}, err
+	}
+
+	quoteLengths, err := parseQuoteLengths(quoteLengthValues)
+	if err != nil {
+		return models.Config{}, err
+	}
+	if err := validateQuoteFlags(modeValue, source, quoteLengths, quoteID); err != nil {
+		return models.Config{}, err
+	}
+
+	metric, err := parseWPMMetric(wpmMetric)
+	if err != nil {
+		return models.Config{}, err
+	}
+
+	difficulty, err := parseDifficulty(difficultyValue)
+	if err != nil {
+		return models.Config{}, err
+	}
+	if modeValue == models.ZenMode && difficulty != models.NormalDifficulty {
+		return models.Config{}, fmt.Errorf("difficulty flag is not available for zen mode")
+	}
+
+	stopOnError, err := parseStopOnError(stopOnErrorValue)
+	if err != nil {
+		return models.Config{}, err
+	}
+	if modeValue == models.ZenMode && stopOnError != models.StopOnErrorOff {
+		return models.Config{}, fmt.Errorf("stop-on-error flag is not available for zen mode")
+	}
+
+	confidence, err := parseConfidence(confidenceValue)
+	if err != nil {
+		return models.Config{}, err
 	}
 
-	cfg := models.Config{
+	return models.Config{
 		Mode:               modeValue,
 		Language:           normalizedLanguage,
 		Duration:           models.Duration(duration),
 		WordCount:          models.WordCount(wordCount),
 		IncludePunctuation: includePunctuation,
 		IncludeNumbers:     includeNumbers,
+		WPMMetric:          metric,
+		Difficulty:         difficulty,
+		StopOnError:        stopOnError,
+		Confidence:         confidence,
+		Custom:             custom,
+		Source:             source,
+		QuoteLengths:       quoteLengths,
+		QuoteID:            quoteID,
+	}, nil
+}
+
+func customOptionsFromFlags(flags *pflag.FlagSet) (models.CustomOptions, error) {
+	file, err := flags.GetString("file")
+	if err != nil {
+		return models.CustomOptions{}, fmt.Errorf("reading file flag: %w", err)
 	}
 
-	if err := app.Run(cfg); err != nil {
-		fmt.Println("Error running app:", err)
+	passageWords, err := flags.GetInt("passage-words")
+	if err != nil {
+		return models.CustomOptions{}, fmt.Errorf("reading passage words flag: %w", err)
 	}
+
+	chunkWords, err := flags.GetInt("chunk-words")
+	if err != nil {
+		return models.CustomOptions{}, fmt.Errorf("reading chunk words flag: %w", err)
+	}
+
+	collapseWhitespace, err := flags.GetBool("collapse-whitespace")
+	if err != nil {
+		return models.CustomOptions{}, fmt.Errorf("reading collapse whitespace flag: %w", err)
+	}
+
+	return models.CustomOptions{
+		File:               file,
+		PassageWords:       passageWords,
+		ChunkWords:         chunkWords,
+		CollapseWhitespace: collapseWhitespace,
+	}, nil
+}
+
+func sourceOptionsFromFlags(flags *pflag.FlagSet) (models.SourceOptions, error) {
+	paths, err := flags.GetStringSlice("source-file")
+	if err != nil {
+		return models.SourceOptions{}, fmt.Errorf("reading source file flag: %w", err)
+	}
+
+	snippet, err := flags.GetString("snippet")
+	if err != nil {
+		return models.SourceOptions{}, fmt.Errorf("reading snippet flag: %w", err)
+	}
+
+	goModule, err := flags.GetString("go-module")
+	if err != nil {
+		return models.SourceOptions{}, fmt.Errorf("reading Go module flag: %w", err)
+	}
+
+	goPackages, err := flags.GetStringSlice("go-package")
+	if err != nil {
+		return models.SourceOptions{}, fmt.Errorf("reading Go package flag: %w", err)
+	}
+
+	lines, err := flags.GetInt("snippet-lines")
+	if err != nil {
+		return models.SourceOptions{}, fmt.Errorf("reading snippet lines flag: %w", err)
+	}
+
+	minLines, err := flags.GetInt("snippet-min-lines")
+	if err != nil {
+		return models.SourceOptions{}, fmt.Errorf("reading snippet min lines flag: %w", err)
+	}
+
+	unit, err := parseSnippetUnit(snippet)
+	if err != nil {
+		return models.SourceOptions{}, err
+	}
+
+	return models.SourceOptions{
+		Paths:      paths,
+		GoModule:   goModule,
+		GoPackages: goPackages,
+		Snippet:    unit,
+		Lines:      lines,
+		MinLines:   minLines,
+	}, nil
 }
 
 func validateFlags(mode models.Mode, duration int, wordCount int, includePunctuation bool, includeNumbers bool) error {
 	switch mode {
-	case models.QuoteMode:
+	case models.QuoteMode, models.CustomMode, models.ZenMode:
 		if duration != defaultDuration {
 			return fmt.Errorf("duration flag is only available for time mode")
 		}
@@ -126,9 +318,91 @@ func validateFlags(mode models.Mode, duration int, wordCount int, includePunctua
 			return fmt.Errorf("word-count flag is only available for words mode")
 		}
 	default:
-		return fmt.Errorf("unsupported mode %q. Supported modes: 'quote', 'words', 'time'", mode)
+		return fmt.Errorf("unsupported mode %q. Supported modes: 'quote', 'words', 'time', 'custom', 'zen'", mode)
+	}
+
+	return nil
+}
+
+func validateCustomFlags(mode models.Mode, custom models.CustomOptions) error {
+	if mode != models.CustomMode {
+		switch {
+		case custom.File != "":
+			return fmt.Errorf("file flag is only available for custom mode")
+		case custom.PassageWords != 0:
+			return fmt.Errorf("passage-words flag is only available for custom mode")
+		case custom.ChunkWords != 0:
+			return fmt.Errorf("chunk-words flag is only available for custom mode")
+		case custom.CollapseWhitespace:
+			return fmt.Errorf("collapse-whitespace flag is only available for custom mode")
+		}
+		return nil
+	}
+
+	if custom.PassageWords < 0 || custom.ChunkWords < 0 {
+		return fmt.Errorf("passage-words and chunk-words must not be negative")
+	}
+	if custom.PassageWords > 0 && custom.ChunkWords > 0 {
+		return fmt.Errorf("passage-words and chunk-words cannot be combined")
+	}
+	return nil
+}
+
+func validateSourceFlags(mode models.Mode, language models.Language, source models.SourceOptions) error {
+	if mode != models.QuoteMode {
+		switch {
+		case len(source.Paths) > 0:
+			return fmt.Errorf("source-file flag is only available for quote mode")
+		case source.GoModule != "":
+			return fmt.Errorf("go-module flag is only available for quote mode")
+		case len(source.GoPackages) > 0:
+			return fmt.Errorf("go-package flag is only available for quote mode")
+		case source.Snippet != models.FunctionSnippets:
+			return fmt.Errorf("snippet flag is only available for quote mode")
+		case source.Lines != defaultSnippetLines:
+			return fmt.Errorf("snippet-lines flag is only available for quote mode")
+		case source.MinLines != defaultSnippetMinLines:
+			return fmt.Errorf("snippet-min-lines flag is only available for quote mode")
+		}
+		return nil
 	}
 
+	if source.GoModule != "" {
+		if len(source.Paths) > 0 {
+			return fmt.Errorf("source-file and go-module cannot be combined")
+		}
+		if source.Snippet != models.FunctionSnippets {
+			return fmt.Errorf("go-module only types declarations; drop the snippet flag")
+		}
+		if strings.HasPrefix(string(language), "code_") && language != models.Go {
+			return fmt.Errorf("go-module types Go code, but the language is %q", language)
+		}
+	} else if len(source.GoPackages) > 0 {
+		return fmt.Errorf("go-package flag requires go-module")
+	}
+
+	if source.MinLines < 1 {
+		return fmt.Errorf("snippet-min-lines must be at least 1")
+	}
+	if source.Lines < source.MinLines {
+		return fmt.Errorf("snippet-lines must be at least snippet-min-lines (%d)", source.MinLines)
+	}
+	return nil
+}
+
+// validateQuoteFlags checks the flags that pick from the built-in quotes.
+func validateQuoteFlags(mode models.Mode, source models.SourceOptions, lengths []models.QuoteLength, quoteID int) error {
+	builtIn := mode == models.QuoteMode && !source.Enabled()
+	switch {
+	case len(lengths) > 0 && !builtIn:
+		return fmt.Errorf("quote-length flag is only available for quote mode with the built-in quotes")
+	case quoteID != 0 && !builtIn:
+		return fmt.Errorf("quote-id flag is only available for quote mode with the built-in quotes")
+	case quoteID < 0:
+		return fmt.Errorf("quote-id must be positive, got %d", quoteID)
+	case quoteID > 0 && len(lengths) > 0:
+		return fmt.Errorf("quote-id and quote-length cannot be combined")
+	}
 	return nil
 }
 
@@ -146,6 +420,77 @@ func normalizeLanguage(language string) (models.Language, error) {
 	return "", fmt.Errorf("unsupported language %q. Supported languages: %s", language, strings.Join(names, ", "))
 }
 
+func parseWPMMetric(value string) (models.WPMMetric, error) {
+	switch metric := models.WPMMetric(strings.ToLower(strings.TrimSpace(value))); metric {
+	case models.CharacterWPM, models.WordWPM:
+		return metric, nil
+	default:
+		return "", fmt.Errorf("unsupported WPM metric %q. Supported metrics: '%s', '%s'", value, models.CharacterWPM, models.WordWPM)
+	}
+}
+
+func parseDifficulty(value string) (models.Difficulty, error) {
+	switch difficulty := models.Difficulty(strings.ToLower(strings.TrimSpace(value))); difficulty {
+	case models.NormalDifficulty, models.ExpertDifficulty, models.MasterDifficulty:
+		return difficulty, nil
+	default:
+		return "", fmt.Errorf("unsupported difficulty %q. Supported difficulties: '%s', '%s', '%s'", value, models.NormalDifficulty, models.ExpertDifficulty, models.MasterDifficulty)
+	}
+}
+
+func parseStopOnError(value string) (models.StopOnError, error) {
+	switch stop := models.StopOnError(strings.ToLower(strings.TrimSpace(value))); stop {
+	case models.StopOnErrorOff, models.StopOnLetter, models.StopOnWord:
+		return stop, nil
+	default:
+		return "", fmt.Errorf("unsupported stop on error %q. Supported values: '%s', '%s', '%s'", value, models.StopOnErrorOff, models.StopOnLetter, models.StopOnWord)
+	}
+}
+
+func parseConfidence(value string) (models.Confidence, error) {
+	switch confidence := models.Confidence(strings.ToLower(strings.TrimSpace(value))); confidence {
+	case models.ConfidenceOff, models.ConfidenceOn, models.ConfidenceMax:
+		return confidence, nil
+	default:
+		return "", fmt.Errorf("unsupported confidence %q. Supported values: '%s', '%s', '%s'", value, models.ConfidenceOff, models.ConfidenceOn, models.ConfidenceMax)
+	}
+}
+
+func parseSnippetUnit(value string) (models.SnippetUnit, error) {
+	switch unit := models.SnippetUnit(strings.ToLower(strings.TrimSpace(value))); unit {
+	case models.FunctionSnippets, models.LineSnippets:
+		return unit, nil
+	default:
+		return "", fmt.Errorf("unsupported snippet %q. Supported snippets: '%s', '%s'", value, models.FunctionSnippets, models.LineSnippets)
+	}
+}
+
+// parseQuoteLengths reads the --quote-length values. "all" selects every
+// length, which is the same as selecting none.
+func parseQuoteLengths(values []string) ([]models.QuoteLength, error) {
+	var lengths []models.QuoteLength
+	seen := make(map[models.QuoteLength]bool)
+	all := false
+	for _, value := range values {
+		length := models.QuoteLength(strings.ToLower(strings.TrimSpace(value)))
+		if length == "all" {
+			all = true
+			continue
+		}
+		if !slices.Contains(models.QuoteLengths(), length) {
+			return nil, fmt.Errorf("unsupported quote length %q. Supported lengths: 'short', 'medium', 'long', 'thicc', 'all'", value)
+		}
+		if !seen[length] {
+			seen[length] = true
+			lengths = append(lengths, length)
+		}
+	}
+	if all {
+		return nil, nil
+	}
+	return lengths, nil
+}
+
 func joinInts(values []int) string {
 	parts := make([]string, len(values))
 	for i, v := range values {
@@ -162,12 +507,35 @@ func Execute() {
 	}
 }
 
+// addTypingFlags registers the flags that describe a typing test. Presets
+// and config files can set any flag added here.
+func addTypingFlags(flags *pflag.FlagSet) {
+	flags.StringP("mode", "m", "quote", "Mode of the typing test ('quote', 'words', 'time', 'custom', 'zen')")
+	flags.StringP("language", "l", "english", "Language for the typing test (e.g., 'english' for English, 'spanish' for Spanish, 'code_go' for Go code)")
+	flags.IntP("duration", "d", 60, "Duration of the typing test in seconds (only for 'time' mode; options: 15, 30, 60, 120)")
+	flags.IntP("word-count", "w", 50, "Number of words for the typing test (only for 'words' mode; options: 10, 25, 50, 100)")
+	flags.BoolP("include-punctuation", "p", false, "Include punctuation in the typing test (only for 'words' and 'time' modes)")
+	flags.BoolP("include-numbers", "n", false, "Include numbers in the typing test (only for 'words' and 'time' modes)")
+	flags.String("wpm-metric", string(models.CharacterWPM), "How WPM is scored ('chars' counts correct characters / 5, 'words' counts whitespace-separated words)")
+	flags.String("difficulty", string(models.NormalDifficulty), "How mistakes are punished ('normal'; 'expert' fails the test when a word is submitted with mistakes; 'master' fails it on the first wrong keystroke)")
+	flags.String("stop-on-error", string(models.StopOnErrorOff), "Block input until a mistake is fixed ('off'; 'letter' rejects wrong keys; 'word' blocks finishing a word that has mistakes)")
+	flags.String("confidence", string(models.ConfidenceOff), "Limit backspace ('off'; 'on' only within the current word; 'max' never)")
+	flags.String("file", "", "Text file to type (only for 'custom' mode; reads standard input when omitted or '-')")
+	flags.Int("passage-words", 0, "Type a random passage of this many words from the text each time (only for 'custom' mode)")
+	flags.Int("chunk-words", 0, "Split the text into chunks of this many words, typed in order (only for 'custom' mode)")
+	flags.Bool("collapse-whitespace", false, "Join lines and squeeze repeated whitespace into single spaces (only for 'custom' mode)")
+	flags.StringSlice("quote-length", nil, "Only type quotes of these lengths: 'short', 'medium', 'long', 'thicc' or 'all' (only for 'quote' mode; default all)")
+	flags.Int("quote-id", 0, "Type the quote with this id every time (only for 'quote' mode; find ids with 'quotes search')")
+	flags.StringSlice("source-file", nil, "Source files, directories or globs to type code from instead of the built-in quotes (only for 'quote' mode)")
+	flags.String("snippet", string(models.FunctionSnippets), "How source files are cut into passages ('functions' falls back to line windows for files without functions, 'lines')")
+	flags.Int("snippet-lines", defaultSnippetLines, "Lines per window and the longest function typed from source files (only for 'quote' mode)")
+	flags.Int("snippet-min-lines", defaultSnippetMinLines, "Shortest function or declaration typed from source files (only for 'quote' mode)")
+	flags.String("go-module", "", "Go module directory whose top-level functions, methods and types are typed, e.g. $(go env GOROOT)/src (only for 'quote' mode)")
+	flags.StringSlice("go-package", nil, "Only type declarations from these import paths; 'net/...' includes subpackages (requires --go-module)")
+}
+
 func init() {
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.typing-test-tui.yaml)")
-	rootCmd.Flags().StringP("mode", "m", "quote", "Mode of the typing test ('quote', 'words', 'time')")
-	rootCmd.Flags().StringP("language", "l", "english", "Language for the typing test (e.g., 'english' for English, 'spanish' for Spanish, 'code_go' for Go code)")
-	rootCmd.Flags().IntP("duration", "d", 60, "Duration of the typing test in seconds (only for 'time' mode; options: 15, 30, 60, 120)")
-	rootCmd.Flags().IntP("word-count", "w", 50, "Number of words for the typing test (only for 'words' mode; options: 10, 25, 50, 100)")
-	rootCmd.Flags().BoolP("include-punctuation", "p", false, "Include punctuation in the typing test (only for 'words' and 'time' modes)")
-	rootCmd.Flags().BoolP("include-numbers", "n", false, "Include numbers in the typing test (only for 'words' and 'time' modes)")
+	rootCmd.PersistentFlags().String("config", "", "Config file with flag defaults (default is $XDG_CONFIG_HOME/typing-test-tui/config.yaml)")
+	addTypingFlags(rootCmd.Flags())
+	rootCmd.Flags().String("preset", "", "Run a saved preset (see 'preset list'); flags passed alongside it take precedence")
 }
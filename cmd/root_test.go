@@ -2,11 +2,18 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/neilsmahajan/typing-test-tui/internal/config"
+	"github.com/neilsmahajan/typing-test-tui/internal/history"
 	"github.com/neilsmahajan/typing-test-tui/internal/models"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func TestValidateFlagsQuoteMode(t *testing.T) {
@@ -45,6 +52,44 @@ func TestNormalizeLanguageUnsupported(t *testing.T) {
 	}
 }
 
+func TestParseWPMMetric(t *testing.T) {
+	metric, err := parseWPMMetric(" Words ")
+	if err != nil {
+		t.Fatalf("expected words metric to parse, got %v", err)
+	}
+	if metric != models.WordWPM {
+		t.Fatalf("expected metric %q, got %q", models.WordWPM, metric)
+	}
+	if _, err := parseWPMMetric("keystrokes"); err == nil {
+		t.Fatalf("expected error for unsupported metric")
+	}
+}
+
+func TestParseDifficulty(t *testing.T) {
+	difficulty, err := parseDifficulty(" Expert ")
+	if err != nil || difficulty != models.ExpertDifficulty {
+		t.Fatalf("expected expert difficulty, got %q (%v)", difficulty, err)
+	}
+	if _, err := parseDifficulty("hard"); err == nil {
+		t.Fatalf("expected error for unsupported difficulty")
+	}
+}
+
+func TestParseStopOnErrorAndConfidence(t *testing.T) {
+	if stop, err := parseStopOnError("Word"); err != nil || stop != models.StopOnWord {
+		t.Fatalf("expected word stop, got %q (%v)", stop, err)
+	}
+	if _, err := parseStopOnError("sentence"); err == nil {
+		t.Fatalf("expected error for unsupported stop on error")
+	}
+	if confidence, err := parseConfidence(" max"); err != nil || confidence != models.ConfidenceMax {
+		t.Fatalf("expected max confidence, got %q (%v)", confidence, err)
+	}
+	if _, err := parseConfidence("always"); err == nil {
+		t.Fatalf("expected error for unsupported confidence")
+	}
+}
+
 func TestJoinInts(t *testing.T) {
 	result := joinInts([]int{1, 2, 3})
 	if result != "1, 2, 3" {
@@ -83,3 +128,496 @@ func TestListModesOutput(t *testing.T) {
 		t.Fatalf("expected all modes in output, got %q", output)
 	}
 }
+
+func TestShowHistoryPlainOutput(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+
+	store, err := history.Default()
+	if err != nil {
+		t.Fatalf("expected default store, got %v", err)
+	}
+	finished := time.Date(2024, 3, 10, 12, 0, 0, 0, time.Local)
+	for _, result := range []models.Result{
+		{Mode: models.TimeMode, Language: models.English, Duration: 30, WPM: 61.5, FinishedAt: finished},
+		{Mode: models.WordsMode, Language: models.English, WordCount: 25, WPM: 75, FinishedAt: finished.AddDate(0, 0, 1)},
+	} {
+		if err := store.Append(result); err != nil {
+			t.Fatalf("expected append to succeed, got %v", err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	historyCmd.SetOut(buf)
+	t.Cleanup(func() {
+		historyCmd.SetOut(nil)
+		historyCmd.Flags().Set("mode", "")
+		historyCmd.Flags().Set("until", "")
+	})
+	historyCmd.Flags().Set("mode", "time")
+	historyCmd.Flags().Set("until", "2024-03-10")
+
+	showHistory(historyCmd, nil)
+
+	output := buf.String()
+	if !strings.Contains(output, "Setting") || !strings.Contains(output, "30s") {
+		t.Fatalf("expected table with the time result, got %q", output)
+	}
+	if strings.Contains(output, "25 words") {
+		t.Fatalf("expected words result to be filtered out, got %q", output)
+	}
+}
+
+func TestShowHistoryEmpty(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	buf := &bytes.Buffer{}
+	historyCmd.SetOut(buf)
+	t.Cleanup(func() { historyCmd.SetOut(nil) })
+
+	showHistory(historyCmd, nil)
+
+	if !strings.Contains(buf.String(), "No results found.") {
+		t.Fatalf("expected empty message, got %q", buf.String())
+	}
+}
+
+func TestPrintStats(t *testing.T) {
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.Local)
+	results := []models.Result{
+		{Mode: models.TimeMode, Language: models.English, WPM: 60, Accuracy: 95, ElapsedSeconds: 30, Outcome: models.OutcomeCompleted, FinishedAt: now.AddDate(0, 0, -8)},
+		{Mode: models.TimeMode, Language: models.English, WPM: 70, Accuracy: 97, ElapsedSeconds: 30, Outcome: models.OutcomeCompleted, FinishedAt: now.AddDate(0, 0, -1)},
+		{Mode: models.WordsMode, Language: models.Spanish, WPM: 80, Accuracy: 99, ElapsedSeconds: 20, Outcome: models.OutcomeCompleted, FinishedAt: now},
+	}
+
+	buf := &bytes.Buffer{}
+	printStats(buf, results, now, 2)
+
+	output := buf.String()
+	for _, want := range []string{"3 tests (3 completed) · 1m20s typed", "Streak: 2 days (longest 2 days)", "All time", "80.0", "spanish", "+15.0"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got %q", want, output)
+		}
+	}
+}
+
+func TestPrintStatsSplitsScales(t *testing.T) {
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.Local)
+	results := []models.Result{
+		{Mode: models.TimeMode, Language: models.English, Unit: "WPM", WPM: 60, Accuracy: 95, Outcome: models.OutcomeCompleted, FinishedAt: now},
+		{Mode: models.TimeMode, Language: models.English, Unit: "WPM", Metric: models.WordWPM, WPM: 90, Accuracy: 95, Outcome: models.OutcomeCompleted, FinishedAt: now},
+		{Mode: models.TimeMode, Language: models.Chinese, Unit: "CPM", WPM: 200, Accuracy: 90, Outcome: models.OutcomeCompleted, FinishedAt: now},
+	}
+
+	buf := &bytes.Buffer{}
+	printStats(buf, results, now, 1)
+
+	output := buf.String()
+	rest, cpm, found := strings.Cut(output, "Speed in CPM")
+	wpm, words, foundWords := strings.Cut(rest, "Speed in WPM (words metric)")
+	if !found || !foundWords || !strings.Contains(wpm, "Speed in WPM\n") {
+		t.Fatalf("expected a section per scale, got %q", output)
+	}
+	if !strings.Contains(wpm, "60.0") || strings.Contains(wpm, "75.0") || !strings.Contains(words, "90.0") || !strings.Contains(cpm, "200.0") {
+		t.Fatalf("expected each scale to be summarised apart, got %q", output)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	weeks := []history.Week{
+		{Summary: history.Summary{Count: 1, AverageWPM: 40}},
+		{},
+		{Summary: history.Summary{Count: 2, AverageWPM: 80}},
+	}
+	if got := sparkline(weeks); got != "▁ █" {
+		t.Fatalf("unexpected sparkline %q", got)
+	}
+}
+
+func TestExportHistoryInfersFormatFromOutput(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	store, err := history.Default()
+	if err != nil {
+		t.Fatalf("expected default store, got %v", err)
+	}
+	if err := store.Append(models.Result{Mode: models.WordsMode, Language: models.English, WordCount: 10, WPM: 55}); err != nil {
+		t.Fatalf("expected append to succeed, got %v", err)
+	}
+
+	output := filepath.Join(t.TempDir(), "results.json")
+	buf := &bytes.Buffer{}
+	exportCmd.SetOut(buf)
+	exportCmd.SetErr(buf)
+	t.Cleanup(func() {
+		exportCmd.SetOut(nil)
+		exportCmd.SetErr(nil)
+		exportCmd.Flags().Set("output", "")
+	})
+	exportCmd.Flags().Set("output", output)
+
+	exportHistory(exportCmd, nil)
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("expected export file, got %v (output %q)", err, buf.String())
+	}
+	var results []models.Result
+	if err := json.Unmarshal(data, &results); err != nil || len(results) != 1 || results[0].WPM != 55 {
+		t.Fatalf("expected one json result, got %q (%v)", data, err)
+	}
+	if !strings.Contains(buf.String(), "Exported 1 results") {
+		t.Fatalf("expected confirmation, got %q", buf.String())
+	}
+}
+
+func TestExportHistoryOldestFirst(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	store, err := history.Default()
+	if err != nil {
+		t.Fatalf("expected default store, got %v", err)
+	}
+	now := time.Now()
+	// An import appends results older than the local ones.
+	if err := store.AppendAll([]models.Result{
+		{ID: "local", Mode: models.TimeMode, FinishedAt: now},
+		{ID: "imported", Mode: models.TimeMode, FinishedAt: now.AddDate(-2, 0, 0)},
+	}); err != nil {
+		t.Fatalf("expected append to succeed, got %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	exportCmd.SetOut(buf)
+	t.Cleanup(func() {
+		exportCmd.SetOut(nil)
+		exportCmd.Flags().Set("format", "csv")
+		exportCmd.Flags().Lookup("format").Changed = false
+	})
+	exportCmd.Flags().Set("format", "ndjson")
+
+	exportHistory(exportCmd, nil)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"id":"imported"`) || !strings.Contains(lines[1], `"id":"local"`) {
+		t.Fatalf("expected results oldest first, got %q", buf.String())
+	}
+}
+
+func TestImportMonkeytypeSkipsDuplicates(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	input := filepath.Join(t.TempDir(), "results.csv")
+	csv := "_id,wpm,acc,mode,mode2,language,timestamp\nabc,70,96,time,60,english,1700000000000\n"
+	if err := os.WriteFile(input, []byte(csv), 0o644); err != nil {
+		t.Fatalf("expected to write fixture, got %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	importMonkeytypeCmd.SetOut(buf)
+	importMonkeytypeCmd.SetErr(buf)
+	t.Cleanup(func() {
+		importMonkeytypeCmd.SetOut(nil)
+		importMonkeytypeCmd.SetErr(nil)
+	})
+
+	importMonkeytype(importMonkeytypeCmd, []string{input})
+	importMonkeytype(importMonkeytypeCmd, []string{input})
+
+	output := buf.String()
+	if !strings.Contains(output, "Imported 1 results (0 already imported") || !strings.Contains(output, "Imported 0 results (1 already imported") {
+		t.Fatalf("unexpected import output %q", output)
+	}
+
+	store, _ := history.Default()
+	results, err := store.Load()
+	if err != nil || len(results) != 1 || results[0].Source != history.SourceMonkeytype {
+		t.Fatalf("expected a single imported result, got %+v (%v)", results, err)
+	}
+}
+
+func TestRelaxModeSpecific(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.Int("duration", defaultDuration, "")
+	flags.Int("word-count", defaultWordCount, "")
+	flags.Set("duration", "30")
+	flags.Set("word-count", "25")
+
+	origins := map[string]config.Origin{
+		"duration":   {Source: config.SourceFile},
+		"word-count": {Source: config.SourceFlag},
+	}
+	relaxModeSpecific(flags, models.QuoteMode, origins)
+
+	if got, _ := flags.GetInt("duration"); got != defaultDuration || origins["duration"].Source != config.SourceDefault {
+		t.Fatalf("expected duration from the config file to be reset, got %d (%s)", got, origins["duration"])
+	}
+	if got, _ := flags.GetInt("word-count"); got != 25 {
+		t.Fatalf("expected explicit word count to be kept for validation, got %d", got)
+	}
+}
+
+func TestShowConfig(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("TYPING_TEST_MODE", "time")
+	dir := filepath.Join(configHome, "typing-test-tui")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("expected to create config dir, got %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("language: spanish\nword-count: 25\n"), 0o644); err != nil {
+		t.Fatalf("expected to write config, got %v", err)
+	}
+	t.Cleanup(func() {
+		rootCmd.Flags().VisitAll(func(flag *pflag.Flag) { resetFlag(flag) })
+	})
+
+	buf := &bytes.Buffer{}
+	configShowCmd.SetOut(buf)
+	t.Cleanup(func() { configShowCmd.SetOut(nil) })
+
+	showConfig(configShowCmd, nil)
+
+	output := buf.String()
+	for _, want := range []string{"mode", "env (TYPING_TEST_MODE)", "spanish", "file (", "wpm-metric"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got %q", want, output)
+		}
+	}
+	// Time mode has no word count, so the file's value is relaxed.
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "word-count") && !strings.Contains(line, "50") {
+			t.Fatalf("expected word count to fall back to its default, got %q", line)
+		}
+	}
+}
+
+func TestSaveAndListPresets(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	buf := &bytes.Buffer{}
+	for _, c := range []*cobra.Command{presetSaveCmd, presetListCmd} {
+		c.SetOut(buf)
+		c.SetErr(buf)
+	}
+	t.Cleanup(func() {
+		for _, c := range []*cobra.Command{presetSaveCmd, presetListCmd} {
+			c.SetOut(nil)
+			c.SetErr(nil)
+		}
+		presetSaveCmd.Flags().VisitAll(func(flag *pflag.Flag) {
+			resetFlag(flag)
+			flag.Changed = false
+		})
+	})
+
+	flags := presetSaveCmd.Flags()
+	flags.Set("mode", "time")
+	flags.Set("language", "go")
+	flags.Set("duration", "30")
+	flags.Set("include-punctuation", "true")
+	savePreset(presetSaveCmd, []string{"go-30s-punct"})
+
+	presets, err := config.DefaultPresets()
+	if err != nil {
+		t.Fatalf("expected presets file, got %v", err)
+	}
+	preset, err := presets.Get("go-30s-punct")
+	if err != nil {
+		t.Fatalf("expected preset to be saved, got %v (output %q)", err, buf.String())
+	}
+	want := map[string]string{"mode": "time", "language": "code_go", "duration": "30", "include-punctuation": "true", "include-numbers": "false", "wpm-metric": "chars", "difficulty": "normal", "stop-on-error": "off", "confidence": "off"}
+	if len(preset.Values) != len(want) {
+		t.Fatalf("expected values %v, got %v", want, preset.Values)
+	}
+	for key, value := range want {
+		if preset.Values[key] != value {
+			t.Fatalf("expected %s=%s, got %v", key, value, preset.Values)
+		}
+	}
+
+	flags.Set("duration", "45")
+	savePreset(presetSaveCmd, []string{"broken"})
+	if _, err := presets.Get("broken"); err == nil {
+		t.Fatalf("expected an invalid configuration not to be saved")
+	}
+
+	buf.Reset()
+	listPresets(presetListCmd, nil)
+	if !strings.Contains(buf.String(), "go-30s-punct") || !strings.Contains(buf.String(), "mode=time") {
+		t.Fatalf("expected preset in list, got %q", buf.String())
+	}
+}
+
+func TestValidateCustomFlags(t *testing.T) {
+	if err := validateFlags(models.CustomMode, defaultDuration, defaultWordCount, false, false); err != nil {
+		t.Fatalf("expected custom mode defaults to be valid, got %v", err)
+	}
+	if err := validateCustomFlags(models.CustomMode, models.CustomOptions{File: "notes.txt", ChunkWords: 50}); err != nil {
+		t.Fatalf("expected custom options to be valid, got %v", err)
+	}
+	if err := validateCustomFlags(models.QuoteMode, models.CustomOptions{File: "notes.txt"}); err == nil || !strings.Contains(err.Error(), "custom mode") {
+		t.Fatalf("expected file flag to be rejected outside custom mode, got %v", err)
+	}
+	if err := validateCustomFlags(models.CustomMode, models.CustomOptions{PassageWords: 10, ChunkWords: 10}); err == nil {
+		t.Fatalf("expected passage and chunk sizes to be exclusive")
+	}
+}
+
+func TestValidateSourceFlags(t *testing.T) {
+	source := models.SourceOptions{Paths: []string{"."}, Snippet: models.LineSnippets, Lines: 10, MinLines: 1}
+	if err := validateSourceFlags(models.QuoteMode, models.English, source); err != nil {
+		t.Fatalf("expected source options to be valid, got %v", err)
+	}
+	if err := validateSourceFlags(models.TimeMode, models.English, source); err == nil || !strings.Contains(err.Error(), "quote mode") {
+		t.Fatalf("expected source-file flag to be rejected outside quote mode, got %v", err)
+	}
+	if err := validateSourceFlags(models.QuoteMode, models.English, models.SourceOptions{Snippet: models.FunctionSnippets, MinLines: 1}); err == nil {
+		t.Fatalf("expected zero snippet lines to be rejected")
+	}
+	if _, err := parseSnippetUnit("classes"); err == nil {
+		t.Fatalf("expected error for unsupported snippet unit")
+	}
+}
+
+func TestRelaxModeSpecificEmptiesSourceFiles(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.StringSlice("source-file", nil, "")
+	flags.Lookup("source-file").Value.Set("main.go")
+
+	origins := map[string]config.Origin{"source-file": {Source: config.SourcePreset}}
+	relaxModeSpecific(flags, models.WordsMode, origins)
+
+	if got, _ := flags.GetStringSlice("source-file"); len(got) != 0 {
+		t.Fatalf("expected source files from a preset to be cleared, got %v", got)
+	}
+}
+
+func TestValidateGoModuleFlags(t *testing.T) {
+	source := models.SourceOptions{GoModule: ".", GoPackages: []string{"net/..."}, Snippet: models.FunctionSnippets, Lines: 40, MinLines: 5}
+	if err := validateSourceFlags(models.QuoteMode, models.English, source); err != nil {
+		t.Fatalf("expected Go module options to be valid, got %v", err)
+	}
+	if err := validateSourceFlags(models.QuoteMode, models.Rust, source); err == nil {
+		t.Fatalf("expected a non-Go code language to be rejected")
+	}
+
+	withFiles := source
+	withFiles.Paths = []string{"main.go"}
+	if err := validateSourceFlags(models.QuoteMode, models.Go, withFiles); err == nil {
+		t.Fatalf("expected source-file and go-module to be exclusive")
+	}
+
+	packagesOnly := models.SourceOptions{GoPackages: []string{"fmt"}, Snippet: models.FunctionSnippets, Lines: 15, MinLines: 1}
+	if err := validateSourceFlags(models.QuoteMode, models.Go, packagesOnly); err == nil || !strings.Contains(err.Error(), "requires go-module") {
+		t.Fatalf("expected go-package without go-module to be rejected, got %v", err)
+	}
+
+	inverted := source
+	inverted.MinLines = 50
+	if err := validateSourceFlags(models.QuoteMode, models.Go, inverted); err == nil {
+		t.Fatalf("expected min lines above max lines to be rejected")
+	}
+}
+
+func TestParseQuoteLengths(t *testing.T) {
+	lengths, err := parseQuoteLengths([]string{"Short", "thicc", "short"})
+	if err != nil {
+		t.Fatalf("expected lengths to parse, got %v", err)
+	}
+	if len(lengths) != 2 || lengths[0] != models.ShortQuotes || lengths[1] != models.ThiccQuotes {
+		t.Fatalf("unexpected lengths %v", lengths)
+	}
+	if lengths, err := parseQuoteLengths([]string{"short", "all"}); err != nil || lengths != nil {
+		t.Fatalf("expected 'all' to select every length, got %v (%v)", lengths, err)
+	}
+	if _, err := parseQuoteLengths([]string{"tiny"}); err == nil {
+		t.Fatalf("expected error for unsupported quote length")
+	}
+}
+
+func TestValidateQuoteFlags(t *testing.T) {
+	if err := validateQuoteFlags(models.QuoteMode, models.SourceOptions{}, nil, 42); err != nil {
+		t.Fatalf("expected quote id to be valid, got %v", err)
+	}
+	if err := validateQuoteFlags(models.WordsMode, models.SourceOptions{}, nil, 42); err == nil {
+		t.Fatalf("expected quote id to be rejected outside quote mode")
+	}
+	if err := validateQuoteFlags(models.QuoteMode, models.SourceOptions{Paths: []string{"main.go"}}, nil, 42); err == nil {
+		t.Fatalf("expected quote id to be rejected with source files")
+	}
+	if err := validateQuoteFlags(models.QuoteMode, models.SourceOptions{}, []models.QuoteLength{models.ShortQuotes}, 42); err == nil {
+		t.Fatalf("expected quote id and quote length to be exclusive")
+	}
+	if err := validateQuoteFlags(models.QuoteMode, models.SourceOptions{}, nil, -1); err == nil {
+		t.Fatalf("expected negative quote id to be rejected")
+	}
+}
+
+func TestPickQuoteOverridesSavedQuoteLength(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	dir := filepath.Join(configHome, "typing-test-tui")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("expected to create config dir, got %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("mode: time\nquote-length: long\n"), 0o644); err != nil {
+		t.Fatalf("expected to write config, got %v", err)
+	}
+	t.Cleanup(func() {
+		rootCmd.Flags().VisitAll(func(flag *pflag.Flag) {
+			resetFlag(flag)
+			flag.Changed = false
+		})
+	})
+
+	flags := rootCmd.Flags()
+	if err := pickQuote(flags, models.English, 3); err != nil {
+		t.Fatalf("expected quote flags to be set, got %v", err)
+	}
+	_, origins, err := loadSettings(rootCmd, flags, "")
+	if err != nil {
+		t.Fatalf("expected settings to load, got %v", err)
+	}
+	cfg, err := configFromFlags(flags, origins)
+	if err != nil {
+		t.Fatalf("expected the picked quote to override the saved quote length, got %v", err)
+	}
+	if cfg.Mode != models.QuoteMode || cfg.QuoteID != 3 || len(cfg.QuoteLengths) != 0 {
+		t.Fatalf("expected quote 3 of any length, got mode %s, id %d, lengths %v", cfg.Mode, cfg.QuoteID, cfg.QuoteLengths)
+	}
+}
+
+func TestSearchQuotesPlainOutput(t *testing.T) {
+	buf := &bytes.Buffer{}
+	quotesSearchCmd.SetOut(buf)
+	t.Cleanup(func() {
+		quotesSearchCmd.SetOut(nil)
+		resetFlag(quotesSearchCmd.Flags().Lookup("quote-length"))
+	})
+	quotesSearchCmd.Flags().Set("quote-length", "short")
+
+	searchQuotes(quotesSearchCmd, []string{"glue", "that", "binds"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) < 2 || !strings.HasPrefix(lines[0], "ID") {
+		t.Fatalf("expected a table of matches, got %q", buf.String())
+	}
+	if fields := strings.Fields(lines[1]); fields[0] != "9" || fields[1] != "short" {
+		t.Fatalf("expected the short quote 9 first, got %q", lines[1])
+	}
+	for _, line := range lines[1:] {
+		if strings.Fields(line)[1] != "short" {
+			t.Fatalf("expected only short quotes, got %q", line)
+		}
+	}
+}
+
+func TestSearchQuotesNoMatches(t *testing.T) {
+	buf := &bytes.Buffer{}
+	quotesSearchCmd.SetOut(buf)
+	t.Cleanup(func() { quotesSearchCmd.SetOut(nil) })
+
+	searchQuotes(quotesSearchCmd, []string{"zzqxv"})
+
+	if !strings.Contains(buf.String(), "No quotes found.") {
+		t.Fatalf("expected empty message, got %q", buf.String())
+	}
+}
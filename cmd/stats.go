@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"text/tabwriter"
+	"time"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/history"
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+	"github.com/spf13/cobra"
+)
+
+const defaultTrendWeeks = 8
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarise saved results",
+	Long: `Summarise your results history: averages and bests over recent tests,
+breakdowns per mode and language, total time typed, your daily streak and a
+weekly trend of average speed.`,
+	Example: `typing-test-tui stats
+typing-test-tui stats --mode time --language english --weeks 12`,
+	Args: cobra.NoArgs,
+	Run:  showStats,
+}
+
+func showStats(cmd *cobra.Command, _ []string) {
+	filter, err := historyFilterFromFlags(cmd)
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+
+	weeks, _ := cmd.Flags().GetInt("weeks")
+	if weeks < 1 {
+		cmd.Println("Error: weeks must be at least 1")
+		return
+	}
+
+	store, err := history.Default()
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+	all, err := store.Load()
+	if err != nil {
+		cmd.Println("Error:", err)
+		return
+	}
+
+	results := history.Query(all, filter, history.SortByDate, 0)
+	printStats(cmd.OutOrStdout(), results, time.Now(), weeks)
+}
+
+func printStats(out io.Writer, results []models.Result, now time.Time, weeks int) {
+	if len(results) == 0 {
+		fmt.Fprintln(out, "No results found.")
+		return
+	}
+
+	overall := history.Summarize(results)
+	fmt.Fprintf(out, "%d tests (%d completed) · %s typed\n", len(results), overall.Count, history.TotalTime(results).Round(time.Second))
+
+	current, longest := history.Streaks(results, now)
+	fmt.Fprintf(out, "Streak: %s (longest %s)\n\n", pluralDays(current), pluralDays(longest))
+
+	// Speeds on different scales, e.g. the CPM of Chinese tests or WPM
+	// scored by words, are not averaged together.
+	scales := history.ByScale(results)
+	for i, scale := range scales {
+		if len(scales) > 1 {
+			if i > 0 {
+				fmt.Fprintln(out)
+			}
+			fmt.Fprintf(out, "Speed in %s\n\n", scale.Scale)
+		}
+		printSpeedStats(out, scale.Results, now, weeks)
+	}
+}
+
+// printSpeedStats prints the averages, breakdowns and weekly trend of results
+// whose speeds share a scale.
+func printSpeedStats(out io.Writer, results []models.Result, now time.Time, weeks int) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Tests\tCount\tAvg Speed\tBest Speed\tAvg Accuracy")
+	writeSummaryRow(w, "Last 10", history.Summarize(history.Latest(results, 10)))
+	writeSummaryRow(w, "Last 100", history.Summarize(history.Latest(results, 100)))
+	writeSummaryRow(w, "All time", history.Summarize(results))
+	w.Flush()
+
+	writeGroups(out, "Mode", history.ByMode(results))
+	writeGroups(out, "Language", history.ByLanguage(results))
+
+	trend := history.WeeklyTrend(results, now, weeks)
+	fmt.Fprintf(out, "\nWeekly trend (average speed): %s\n", sparkline(trend))
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Week of\tCount\tAvg Speed\tChange")
+	previous := 0.0
+	for _, week := range trend {
+		if week.Count == 0 {
+			fmt.Fprintf(w, "%s\t0\t-\t-\n", week.Start.Format(historyDateLayout))
+			continue
+		}
+		change := "-"
+		if previous > 0 {
+			change = fmt.Sprintf("%+.1f", week.AverageWPM-previous)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%.1f\t%s\n", week.Start.Format(historyDateLayout), week.Count, week.AverageWPM, change)
+		previous = week.AverageWPM
+	}
+	w.Flush()
+}
+
+func writeSummaryRow(w io.Writer, label string, summary history.Summary) {
+	if summary.Count == 0 {
+		fmt.Fprintf(w, "%s\t0\t-\t-\t-\n", label)
+		return
+	}
+	fmt.Fprintf(w, "%s\t%d\t%.1f\t%.1f\t%.1f%%\n", label, summary.Count, summary.AverageWPM, summary.BestWPM, summary.AverageAccuracy)
+}
+
+func writeGroups(out io.Writer, title string, groups []history.Group) {
+	if len(groups) == 0 {
+		return
+	}
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\tCount\tAvg Speed\tBest Speed\tAvg Accuracy\n", title)
+	for _, group := range groups {
+		writeSummaryRow(w, group.Name, group.Summary)
+	}
+	w.Flush()
+}
+
+// sparkline scales weekly averages between the slowest and fastest week.
+// Weeks without results are drawn as gaps.
+func sparkline(weeks []history.Week) string {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, week := range weeks {
+		if week.Count > 0 {
+			low = math.Min(low, week.AverageWPM)
+			high = math.Max(high, week.AverageWPM)
+		}
+	}
+
+	line := make([]rune, len(weeks))
+	for i, week := range weeks {
+		switch {
+		case week.Count == 0:
+			line[i] = ' '
+		case high == low:
+			line[i] = sparkBlocks[len(sparkBlocks)/2]
+		default:
+			level := int(math.Round((week.AverageWPM - low) / (high - low) * float64(len(sparkBlocks)-1)))
+			line[i] = sparkBlocks[level]
+		}
+	}
+	return string(line)
+}
+
+func pluralDays(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+func init() {
+	addHistoryFilterFlags(statsCmd)
+	statsCmd.Flags().Int("weeks", defaultTrendWeeks, "Number of weeks shown in the trend")
+	rootCmd.AddCommand(statsCmd)
+}
@@ -4,12 +4,23 @@ import (
 	"fmt"
 
 	"github.com/neilsmahajan/typing-test-tui/internal/models"
+	"github.com/neilsmahajan/typing-test-tui/internal/modes/custom"
 	"github.com/neilsmahajan/typing-test-tui/internal/modes/quote"
 	"github.com/neilsmahajan/typing-test-tui/internal/modes/time"
 	"github.com/neilsmahajan/typing-test-tui/internal/modes/words"
+	"github.com/neilsmahajan/typing-test-tui/internal/modes/zen"
+	"github.com/neilsmahajan/typing-test-tui/internal/ui/typing"
 )
 
-func Run(cfg models.Config) error {
+func Run(cfg models.Config) (err error) {
+	// Results are saved in the background, and a program that quits right
+	// after a test may not have saved its last one yet.
+	defer func() {
+		if saveErr := typing.FlushSaves(); saveErr != nil && err == nil {
+			err = fmt.Errorf("error saving result: %w", saveErr)
+		}
+	}()
+
 	switch cfg.Mode {
 	case models.QuoteMode:
 		return quote.Run(cfg)
@@ -17,6 +28,10 @@ func Run(cfg models.Config) error {
 		return words.Run(cfg)
 	case models.TimeMode:
 		return time.Run(cfg)
+	case models.CustomMode:
+		return custom.Run(cfg)
+	case models.ZenMode:
+		return zen.Run(cfg)
 	default:
 		return fmt.Errorf("unsupported mode: %s", cfg.Mode)
 	}
@@ -0,0 +1,223 @@
+// Package config supplies defaults for command-line flags from a YAML
+// config file and TYPING_TEST_* environment variables.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	appDirName     = "typing-test-tui"
+	configFileName = "config.yaml"
+
+	// EnvPrefix starts every environment variable read by Apply.
+	EnvPrefix = "TYPING_TEST_"
+	// PathEnv names an alternative config file, like --config.
+	PathEnv = EnvPrefix + "CONFIG"
+)
+
+// Source tells where a flag's effective value came from, in increasing order
+// of precedence.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+	SourcePreset  Source = "preset"
+	SourceFlag    Source = "flag"
+)
+
+// Origin records the source of a value along with the file path or
+// environment variable that supplied it.
+type Origin struct {
+	Source Source
+	Detail string
+}
+
+func (o Origin) String() string {
+	if o.Detail == "" {
+		return string(o.Source)
+	}
+	return fmt.Sprintf("%s (%s)", o.Source, o.Detail)
+}
+
+// File is a loaded config file: flag names mapped to their values.
+type File struct {
+	Path   string
+	Found  bool
+	Values map[string]string
+}
+
+// DefaultPath resolves the config file inside $XDG_CONFIG_HOME, falling back
+// to ~/.config when the variable is unset.
+func DefaultPath() (string, error) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("config: resolve config directory: %w", err)
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, appDirName, configFileName), nil
+}
+
+// Load reads the config file at explicit, or at $TYPING_TEST_CONFIG, or at
+// DefaultPath. Only the default file may be missing.
+func Load(explicit string, lookupEnv func(string) (string, bool)) (File, error) {
+	path := explicit
+	if path == "" {
+		path, _ = lookupEnv(PathEnv)
+	}
+	required := path != ""
+	if !required {
+		var err error
+		if path, err = DefaultPath(); err != nil {
+			return File{}, err
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return File{Path: path}, nil
+		}
+		return File{}, fmt.Errorf("config: read %s: %w", path, err)
+	}
+
+	values, err := parse(data)
+	if err != nil {
+		return File{}, fmt.Errorf("config: parse %s: %w", path, err)
+	}
+	return File{Path: path, Found: true, Values: values}, nil
+}
+
+// parse decodes a YAML mapping of flag names to values. Keys may use
+// underscores in place of dashes.
+func parse(data []byte) (map[string]string, error) {
+	var raw map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	return decodeValues(raw)
+}
+
+// decodeValues flattens YAML values into flag values. Lists, for flags that
+// take several values, are joined with commas as on the command line.
+func decodeValues(raw map[string]yaml.Node) (map[string]string, error) {
+	values := make(map[string]string, len(raw))
+	for key, node := range raw {
+		switch node.Kind {
+		case yaml.ScalarNode:
+			values[normalizeKey(key)] = node.Value
+		case yaml.SequenceNode:
+			items := make([]string, len(node.Content))
+			for i, item := range node.Content {
+				if item.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("%s must be a list of single values", key)
+				}
+				items[i] = item.Value
+			}
+			values[normalizeKey(key)] = strings.Join(items, ",")
+		default:
+			return nil, fmt.Errorf("%s must be a single value or a list", key)
+		}
+	}
+	return values, nil
+}
+
+func normalizeKey(key string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(key)), "_", "-")
+}
+
+// EnvName returns the environment variable that supplies flag, e.g.
+// TYPING_TEST_WORD_COUNT for word-count.
+func EnvName(flag string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// Apply fills every flag the user did not pass from preset, the environment
+// or the config file, in that order of precedence, and reports where each
+// value came from. Flags named in skip are left alone and not reported.
+// Values applied this way do not mark flags as changed.
+func Apply(flags *pflag.FlagSet, preset Preset, file File, lookupEnv func(string) (string, bool), skip ...string) (map[string]Origin, error) {
+	skipped := make(map[string]bool, len(skip))
+	for _, name := range skip {
+		skipped[name] = true
+	}
+
+	if err := checkKeys(flags, file.Values, skipped, "in "+file.Path); err != nil {
+		return nil, err
+	}
+	if err := checkKeys(flags, preset.Values, skipped, "in preset "+preset.Name); err != nil {
+		return nil, err
+	}
+
+	origins := make(map[string]Origin)
+	var err error
+	set := func(flag *pflag.Flag, value, where string, origin Origin) bool {
+		if setErr := flag.Value.Set(value); setErr != nil {
+			err = fmt.Errorf("config: invalid value %q for %s %s: %w", value, flag.Name, where, setErr)
+			return false
+		}
+		origins[flag.Name] = origin
+		return true
+	}
+
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || skipped[flag.Name] {
+			return
+		}
+		if flag.Changed {
+			origins[flag.Name] = Origin{Source: SourceFlag}
+			return
+		}
+
+		if value, ok := preset.Values[flag.Name]; ok {
+			set(flag, value, "in preset "+preset.Name, Origin{Source: SourcePreset, Detail: preset.Name})
+			return
+		}
+
+		env := EnvName(flag.Name)
+		if value, ok := lookupEnv(env); ok {
+			set(flag, value, "from "+env, Origin{Source: SourceEnv, Detail: env})
+			return
+		}
+
+		if value, ok := file.Values[flag.Name]; ok {
+			set(flag, value, "in "+file.Path, Origin{Source: SourceFile, Detail: file.Path})
+			return
+		}
+
+		origins[flag.Name] = Origin{Source: SourceDefault}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return origins, nil
+}
+
+// checkKeys rejects settings that do not name an applicable flag, so typos
+// are reported instead of silently ignored.
+func checkKeys(flags *pflag.FlagSet, values map[string]string, skipped map[string]bool, where string) error {
+	var unknown []string
+	for key := range values {
+		if flags.Lookup(key) == nil || skipped[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("config: unknown setting %q %s", unknown[0], where)
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func testFlags() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("mode", "quote", "")
+	flags.Int("word-count", 50, "")
+	flags.Bool("include-numbers", false, "")
+	flags.String("language", "english", "")
+	return flags
+}
+
+func envFrom(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+}
+
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("expected to write config, got %v", err)
+	}
+	return path
+}
+
+func TestApplyPrecedence(t *testing.T) {
+	path := writeConfig(t, "mode: words\nword_count: 25\ninclude-numbers: true\n")
+	file, err := Load(path, envFrom(nil))
+	if err != nil {
+		t.Fatalf("expected config to load, got %v", err)
+	}
+
+	flags := testFlags()
+	if err := flags.Parse([]string{"--mode", "time"}); err != nil {
+		t.Fatalf("expected flags to parse, got %v", err)
+	}
+	origins, err := Apply(flags, Preset{}, file, envFrom(map[string]string{"TYPING_TEST_WORD_COUNT": "100"}))
+	if err != nil {
+		t.Fatalf("expected apply to succeed, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		value  string
+		source Source
+	}{
+		{"mode", "time", SourceFlag},
+		{"word-count", "100", SourceEnv},
+		{"include-numbers", "true", SourceFile},
+		{"language", "english", SourceDefault},
+	}
+	for _, tt := range tests {
+		if got := flags.Lookup(tt.name).Value.String(); got != tt.value {
+			t.Fatalf("expected %s to be %q, got %q", tt.name, tt.value, got)
+		}
+		if origins[tt.name].Source != tt.source {
+			t.Fatalf("expected %s to come from %s, got %s", tt.name, tt.source, origins[tt.name])
+		}
+	}
+	if origins["word-count"].Detail != "TYPING_TEST_WORD_COUNT" || origins["include-numbers"].Detail != path {
+		t.Fatalf("expected origins to name their env var and file, got %+v", origins)
+	}
+	if flags.Changed("word-count") {
+		t.Fatalf("expected applied defaults not to mark flags as changed")
+	}
+}
+
+func TestApplyRejectsUnknownAndInvalidSettings(t *testing.T) {
+	file := File{Path: "config.yaml", Found: true, Values: map[string]string{"colour": "red"}}
+	if _, err := Apply(testFlags(), Preset{}, file, envFrom(nil)); err == nil || !strings.Contains(err.Error(), `"colour"`) {
+		t.Fatalf("expected unknown setting error, got %v", err)
+	}
+
+	file.Values = map[string]string{"mode": "words"}
+	if _, err := Apply(testFlags(), Preset{}, file, envFrom(nil), "mode"); err == nil {
+		t.Fatalf("expected skipped flags to be rejected in the config file")
+	}
+
+	if _, err := Apply(testFlags(), Preset{}, File{}, envFrom(map[string]string{"TYPING_TEST_WORD_COUNT": "many"})); err == nil || !strings.Contains(err.Error(), "TYPING_TEST_WORD_COUNT") {
+		t.Fatalf("expected invalid env value error, got %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	file, err := Load("", envFrom(nil))
+	if err != nil || file.Found {
+		t.Fatalf("expected a missing default file to be fine, got %+v (%v)", file, err)
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml"), envFrom(nil)); err == nil {
+		t.Fatalf("expected an explicit missing file to fail")
+	}
+
+	path := writeConfig(t, "language: spanish\n")
+	file, err = Load("", envFrom(map[string]string{PathEnv: path}))
+	if err != nil || !file.Found || file.Values["language"] != "spanish" {
+		t.Fatalf("expected %s to pick the config file, got %+v (%v)", PathEnv, file, err)
+	}
+
+	file, err = Load(writeConfig(t, "source-file: [a.go, b.go]\n"), envFrom(nil))
+	if err != nil || file.Values["source-file"] != "a.go,b.go" {
+		t.Fatalf("expected lists to be joined with commas, got %+v (%v)", file.Values, err)
+	}
+	if _, err := Load(writeConfig(t, "language: {name: english}\n"), envFrom(nil)); err == nil {
+		t.Fatalf("expected nested values to be rejected")
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	if got := EnvName("include-punctuation"); got != "TYPING_TEST_INCLUDE_PUNCTUATION" {
+		t.Fatalf("unexpected env name %q", got)
+	}
+}
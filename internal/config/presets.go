@@ -0,0 +1,160 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+const presetsFileName = "presets.yaml"
+
+var presetNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Preset is a named set of flag values, e.g. a full test configuration the
+// team compares results on.
+type Preset struct {
+	Name   string
+	Values map[string]string
+}
+
+// Presets stores named presets in a YAML file that maps each name to its
+// flag values.
+type Presets struct {
+	path string
+}
+
+func NewPresets(path string) *Presets {
+	return &Presets{path: path}
+}
+
+// DefaultPresets returns the presets file next to the default config file.
+func DefaultPresets() (*Presets, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return NewPresets(filepath.Join(filepath.Dir(path), presetsFileName)), nil
+}
+
+func (p *Presets) Path() string {
+	return p.path
+}
+
+// ValidatePresetName rejects names that would be awkward to type or store.
+func ValidatePresetName(name string) error {
+	if !presetNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid preset name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+// List returns every saved preset sorted by name. A missing file has none.
+func (p *Presets) List() ([]Preset, error) {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("config: read %s: %w", p.path, err)
+	}
+
+	var raw map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("config: parse %s: %w", p.path, err)
+	}
+
+	presets := make([]Preset, 0, len(raw))
+	for name, node := range raw {
+		var fields map[string]yaml.Node
+		if err := node.Decode(&fields); err != nil {
+			return nil, fmt.Errorf("config: parse preset %q in %s: %w", name, p.path, err)
+		}
+		values, err := decodeValues(fields)
+		if err != nil {
+			return nil, fmt.Errorf("config: parse preset %q in %s: %w", name, p.path, err)
+		}
+		presets = append(presets, Preset{Name: name, Values: values})
+	}
+	sort.Slice(presets, func(i, j int) bool { return presets[i].Name < presets[j].Name })
+	return presets, nil
+}
+
+// Get returns the preset called name.
+func (p *Presets) Get(name string) (Preset, error) {
+	presets, err := p.List()
+	if err != nil {
+		return Preset{}, err
+	}
+	for _, preset := range presets {
+		if preset.Name == name {
+			return preset, nil
+		}
+	}
+	return Preset{}, fmt.Errorf("preset %q not found. Run 'typing-test-tui preset list' to see saved presets", name)
+}
+
+// Save adds preset to the file, replacing any preset with the same name.
+// It reports whether an existing preset was replaced.
+func (p *Presets) Save(preset Preset) (bool, error) {
+	if err := ValidatePresetName(preset.Name); err != nil {
+		return false, err
+	}
+	presets, err := p.List()
+	if err != nil {
+		return false, err
+	}
+
+	replaced := false
+	for i := range presets {
+		if presets[i].Name == preset.Name {
+			presets[i] = preset
+			replaced = true
+		}
+	}
+	if !replaced {
+		presets = append(presets, preset)
+		sort.Slice(presets, func(i, j int) bool { return presets[i].Name < presets[j].Name })
+	}
+
+	data, err := yaml.Marshal(presetsNode(presets))
+	if err != nil {
+		return false, fmt.Errorf("config: encode presets: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(p.path), 0o755); err != nil {
+		return false, fmt.Errorf("config: create directory: %w", err)
+	}
+	if err := os.WriteFile(p.path, data, 0o644); err != nil {
+		return false, fmt.Errorf("config: write %s: %w", p.path, err)
+	}
+	return replaced, nil
+}
+
+// presetsNode builds the YAML document by hand so values stay unquoted and
+// keys keep a stable order.
+func presetsNode(presets []Preset) *yaml.Node {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, preset := range presets {
+		keys := make([]string, 0, len(preset.Values))
+		for key := range preset.Values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		values := &yaml.Node{Kind: yaml.MappingNode}
+		for _, key := range keys {
+			value := &yaml.Node{Kind: yaml.ScalarNode, Value: preset.Values[key]}
+			if value.Value == "" {
+				// A bare empty value would read back as null.
+				value.Style = yaml.DoubleQuotedStyle
+			}
+			values.Content = append(values.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+		}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: preset.Name}, values)
+	}
+	return root
+}
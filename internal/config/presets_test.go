@@ -0,0 +1,94 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPresetsSaveAndGet(t *testing.T) {
+	presets := NewPresets(filepath.Join(t.TempDir(), "nested", presetsFileName))
+
+	if saved, err := presets.List(); err != nil || len(saved) != 0 {
+		t.Fatalf("expected no presets in a missing file, got %v (%v)", saved, err)
+	}
+
+	weekly := Preset{Name: "go-code-60s-punct", Values: map[string]string{"mode": "time", "duration": "60", "include-punctuation": "true", "file": ""}}
+	if replaced, err := presets.Save(weekly); err != nil || replaced {
+		t.Fatalf("expected a new preset to be saved, got replaced=%v (%v)", replaced, err)
+	}
+	if _, err := presets.Save(Preset{Name: "quote", Values: map[string]string{"mode": "quote"}}); err != nil {
+		t.Fatalf("expected second preset to be saved, got %v", err)
+	}
+
+	data, err := os.ReadFile(presets.Path())
+	if err != nil {
+		t.Fatalf("expected presets file, got %v", err)
+	}
+	if !strings.Contains(string(data), "duration: 60\n") {
+		t.Fatalf("expected unquoted values, got %q", data)
+	}
+
+	got, err := presets.Get("go-code-60s-punct")
+	if err != nil {
+		t.Fatalf("expected preset to be found, got %v", err)
+	}
+	if got.Values["duration"] != "60" || got.Values["include-punctuation"] != "true" || got.Values["file"] != "" {
+		t.Fatalf("unexpected preset values: %+v", got.Values)
+	}
+
+	weekly.Values = map[string]string{"mode": "words"}
+	if replaced, err := presets.Save(weekly); err != nil || !replaced {
+		t.Fatalf("expected preset to be replaced, got replaced=%v (%v)", replaced, err)
+	}
+	saved, err := presets.List()
+	if err != nil || len(saved) != 2 || saved[0].Name != "go-code-60s-punct" || saved[0].Values["mode"] != "words" {
+		t.Fatalf("unexpected presets after replace: %+v (%v)", saved, err)
+	}
+
+	if _, err := presets.Get("missing"); err == nil {
+		t.Fatalf("expected missing preset error")
+	}
+}
+
+func TestValidatePresetName(t *testing.T) {
+	for _, name := range []string{"weekly", "go-code-60s_punct.v2"} {
+		if err := ValidatePresetName(name); err != nil {
+			t.Fatalf("expected %q to be valid, got %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "-flag", "two words", "a/b"} {
+		if err := ValidatePresetName(name); err == nil {
+			t.Fatalf("expected %q to be rejected", name)
+		}
+	}
+}
+
+func TestApplyPresetPrecedence(t *testing.T) {
+	flags := testFlags()
+	if err := flags.Parse([]string{"--language", "french"}); err != nil {
+		t.Fatalf("expected flags to parse, got %v", err)
+	}
+	preset := Preset{Name: "weekly", Values: map[string]string{"mode": "words", "language": "spanish"}}
+	env := envFrom(map[string]string{"TYPING_TEST_MODE": "time", "TYPING_TEST_WORD_COUNT": "10"})
+
+	origins, err := Apply(flags, preset, File{}, env)
+	if err != nil {
+		t.Fatalf("expected apply to succeed, got %v", err)
+	}
+	if got, _ := flags.GetString("mode"); got != "words" || origins["mode"] != (Origin{Source: SourcePreset, Detail: "weekly"}) {
+		t.Fatalf("expected preset to beat the environment, got %q from %s", got, origins["mode"])
+	}
+	if got, _ := flags.GetString("language"); got != "french" || origins["language"].Source != SourceFlag {
+		t.Fatalf("expected flag to beat the preset, got %q from %s", got, origins["language"])
+	}
+	if origins["word-count"].Source != SourceEnv {
+		t.Fatalf("expected settings missing from the preset to fall through, got %s", origins["word-count"])
+	}
+
+	preset.Values = map[string]string{"colour": "red"}
+	if _, err := Apply(testFlags(), preset, File{}, envFrom(nil)); err == nil || !strings.Contains(err.Error(), "preset weekly") {
+		t.Fatalf("expected unknown preset setting error, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package history
+
+import "github.com/neilsmahajan/typing-test-tui/internal/models"
+
+// Key identifies the test configuration a personal best belongs to. Duration
+// and WordCount are only set for the modes that use them. Speeds scored with
+// different WPM metrics are not comparable, so the metric is part of the key.
+type Key struct {
+	Mode        models.Mode
+	Language    models.Language
+	Duration    models.Duration
+	WordCount   models.WordCount
+	Punctuation bool
+	Numbers     bool
+	Metric      models.WPMMetric
+}
+
+// KeyOf returns the configuration a stored result ran under.
+func KeyOf(result models.Result) Key {
+	return Key{
+		Mode:        result.Mode,
+		Language:    result.Language,
+		Duration:    result.Duration,
+		WordCount:   result.WordCount,
+		Punctuation: result.Punctuation,
+		Numbers:     result.Numbers,
+		Metric:      metricOrDefault(result.Metric),
+	}
+}
+
+// ConfigKey returns the key results of a test started with cfg are stored under.
+func ConfigKey(cfg models.Config) Key {
+	key := Key{
+		Mode:        cfg.Mode,
+		Language:    cfg.Language,
+		Punctuation: cfg.IncludePunctuation,
+		Numbers:     cfg.IncludeNumbers,
+		Metric:      metricOrDefault(cfg.WPMMetric),
+	}
+	switch cfg.Mode {
+	case models.TimeMode:
+		key.Duration = cfg.Duration
+	case models.WordsMode:
+		key.WordCount = cfg.WordCount
+	}
+	return key
+}
+
+// metricOrDefault treats results stored before the metric was recorded as
+// scored by characters, the default.
+func metricOrDefault(metric models.WPMMetric) models.WPMMetric {
+	if metric == "" {
+		return models.CharacterWPM
+	}
+	return metric
+}
+
+// Counts reports whether a result is eligible for personal bests. Tests that
+// were ended early or failed do not count.
+func Counts(result models.Result) bool {
+	return result.Outcome == models.OutcomeCompleted
+}
+
+// PersonalBests returns the fastest eligible result for every configuration.
+func PersonalBests(results []models.Result) map[Key]models.Result {
+	bests := make(map[Key]models.Result)
+	for _, result := range results {
+		if !Counts(result) {
+			continue
+		}
+		key := KeyOf(result)
+		if best, ok := bests[key]; !ok || result.WPM > best.WPM {
+			bests[key] = result
+		}
+	}
+	return bests
+}
+
+// PersonalBest returns the fastest eligible result stored under key.
+func PersonalBest(results []models.Result, key Key) (models.Result, bool) {
+	best, ok := PersonalBests(results)[key]
+	return best, ok
+}
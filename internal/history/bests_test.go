@@ -0,0 +1,63 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+func TestPersonalBest(t *testing.T) {
+	results := []models.Result{
+		{ID: "slow", Mode: models.TimeMode, Language: models.English, Duration: 30, WPM: 60, Outcome: models.OutcomeCompleted},
+		{ID: "fast", Mode: models.TimeMode, Language: models.English, Duration: 30, WPM: 80, Outcome: models.OutcomeCompleted},
+		{ID: "early", Mode: models.TimeMode, Language: models.English, Duration: 30, WPM: 120, Outcome: models.OutcomeIncomplete},
+		{ID: "punct", Mode: models.TimeMode, Language: models.English, Duration: 30, WPM: 100, Punctuation: true, Outcome: models.OutcomeCompleted},
+		{ID: "long", Mode: models.TimeMode, Language: models.English, Duration: 60, WPM: 90, Outcome: models.OutcomeCompleted},
+	}
+
+	key := ConfigKey(models.Config{Mode: models.TimeMode, Language: models.English, Duration: 30, WordCount: 50})
+	best, ok := PersonalBest(results, key)
+	if !ok || best.ID != "fast" {
+		t.Fatalf("expected fast result to be the personal best, got %+v (%v)", best, ok)
+	}
+
+	if _, ok := PersonalBest(results, ConfigKey(models.Config{Mode: models.WordsMode, Language: models.English, WordCount: 25})); ok {
+		t.Fatalf("expected no personal best for an unplayed configuration")
+	}
+
+	if got := len(PersonalBests(results)); got != 3 {
+		t.Fatalf("expected 3 configurations with bests, got %d", got)
+	}
+}
+
+func TestConfigKeyMatchesStoredResults(t *testing.T) {
+	cfg := models.Config{Mode: models.QuoteMode, Language: models.Spanish, Duration: 60, WordCount: 50}
+	stored := models.Result{Mode: models.QuoteMode, Language: models.Spanish, QuoteID: 4}
+	if ConfigKey(cfg) != KeyOf(stored) {
+		t.Fatalf("expected quote config key %+v to match stored key %+v", ConfigKey(cfg), KeyOf(stored))
+	}
+}
+
+func TestPersonalBestKeepsWPMMetricsApart(t *testing.T) {
+	results := []models.Result{
+		{ID: "legacy", Mode: models.TimeMode, Language: models.English, Duration: 30, WPM: 70, Outcome: models.OutcomeCompleted},
+		{ID: "chars", Mode: models.TimeMode, Language: models.English, Duration: 30, WPM: 75, Metric: models.CharacterWPM, Outcome: models.OutcomeCompleted},
+		{ID: "words", Mode: models.TimeMode, Language: models.English, Duration: 30, WPM: 90, Metric: models.WordWPM, Outcome: models.OutcomeCompleted},
+	}
+
+	cfg := models.Config{Mode: models.TimeMode, Language: models.English, Duration: 30}
+	best, ok := PersonalBest(results, ConfigKey(cfg))
+	if !ok || best.ID != "chars" {
+		t.Fatalf("expected the character metric best without a metric set, got %+v (%v)", best, ok)
+	}
+
+	cfg.WPMMetric = models.WordWPM
+	best, ok = PersonalBest(results, ConfigKey(cfg))
+	if !ok || best.ID != "words" {
+		t.Fatalf("expected the word metric best, got %+v (%v)", best, ok)
+	}
+
+	if got := len(PersonalBests(results)); got != 2 {
+		t.Fatalf("expected legacy results to share the character metric best, got %d bests", got)
+	}
+}
@@ -0,0 +1,154 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+// Format is a machine-readable encoding for exported results.
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+)
+
+func ParseFormat(value string) (Format, error) {
+	switch format := Format(strings.ToLower(strings.TrimSpace(value))); format {
+	case FormatCSV, FormatJSON, FormatNDJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q. Supported formats: '%s', '%s', '%s'", value, FormatCSV, FormatJSON, FormatNDJSON)
+	}
+}
+
+// Fields lists the exported field names in CSV column order. They match the
+// JSON keys of models.Result and are part of the export contract: new fields
+// may be appended but existing ones are never renamed or reordered.
+var Fields = []string{
+	"id",
+	"mode",
+	"language",
+	"duration",
+	"word_count",
+	"punctuation",
+	"numbers",
+	"quote_id",
+	"outcome",
+	"wpm_metric",
+	"unit",
+	"wpm",
+	"raw_wpm",
+	"net_wpm",
+	"cpm",
+	"accuracy",
+	"consistency",
+	"correct_chars",
+	"incorrect_chars",
+	"extra_chars",
+	"missed_chars",
+	"started_at",
+	"finished_at",
+	"elapsed_seconds",
+	"source",
+	"external_id",
+	"difficulty",
+}
+
+// Export writes results to w in format.
+func Export(w io.Writer, results []models.Result, format Format) error {
+	switch format {
+	case FormatCSV:
+		return exportCSV(w, results)
+	case FormatJSON:
+		if results == nil {
+			results = []models.Result{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			return fmt.Errorf("history: encode json: %w", err)
+		}
+		return nil
+	case FormatNDJSON:
+		encoder := json.NewEncoder(w)
+		for _, result := range results {
+			if err := encoder.Encode(result); err != nil {
+				return fmt.Errorf("history: encode ndjson: %w", err)
+			}
+		}
+		return nil
+	default:
+		_, err := ParseFormat(string(format))
+		return err
+	}
+}
+
+func exportCSV(w io.Writer, results []models.Result) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(Fields); err != nil {
+		return fmt.Errorf("history: write csv: %w", err)
+	}
+	for _, result := range results {
+		if err := writer.Write(csvRecord(result)); err != nil {
+			return fmt.Errorf("history: write csv: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("history: write csv: %w", err)
+	}
+	return nil
+}
+
+// csvRecord formats result in the order of Fields.
+func csvRecord(result models.Result) []string {
+	return []string{
+		result.ID,
+		string(result.Mode),
+		string(result.Language),
+		strconv.Itoa(int(result.Duration)),
+		strconv.Itoa(int(result.WordCount)),
+		strconv.FormatBool(result.Punctuation),
+		strconv.FormatBool(result.Numbers),
+		strconv.Itoa(result.QuoteID),
+		string(result.Outcome),
+		string(result.Metric),
+		result.Unit,
+		formatFloat(result.WPM),
+		formatFloat(result.RawWPM),
+		formatFloat(result.NetWPM),
+		formatFloat(result.CPM),
+		formatFloat(result.Accuracy),
+		formatFloat(result.Consistency),
+		strconv.Itoa(result.CorrectChars),
+		strconv.Itoa(result.IncorrectChars),
+		strconv.Itoa(result.ExtraChars),
+		strconv.Itoa(result.MissedChars),
+		formatTime(result.StartedAt),
+		formatTime(result.FinishedAt),
+		formatFloat(result.ElapsedSeconds),
+		result.Source,
+		result.ExternalID,
+		string(result.Difficulty),
+	}
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func formatTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+	return value.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,121 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+func exportFixture() []models.Result {
+	finished := time.Date(2024, 6, 1, 10, 30, 0, 0, time.UTC)
+	return []models.Result{
+		{ID: "one", Mode: models.TimeMode, Language: models.English, Duration: 30, Punctuation: true, Outcome: models.OutcomeCompleted, Metric: models.CharacterWPM, Unit: "WPM", WPM: 72.25, Accuracy: 98.5, CorrectChars: 180, StartedAt: finished.Add(-30 * time.Second), FinishedAt: finished, ElapsedSeconds: 30},
+		{ID: "two", Mode: models.QuoteMode, Language: models.Spanish, QuoteID: 9, Outcome: models.OutcomeIncomplete, WPM: 40},
+	}
+}
+
+func TestFieldsMatchResultJSONKeys(t *testing.T) {
+	resultType := reflect.TypeOf(models.Result{})
+	if resultType.NumField() != len(Fields) {
+		t.Fatalf("expected %d fields, got %d", resultType.NumField(), len(Fields))
+	}
+	for i, field := range Fields {
+		tag := resultType.Field(i).Tag.Get("json")
+		if tag != field {
+			t.Fatalf("expected field %d to be tagged %q, got %q", i, field, tag)
+		}
+	}
+	if len(csvRecord(models.Result{})) != len(Fields) {
+		t.Fatalf("expected csv records to have one value per field")
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(&buf, exportFixture(), FormatCSV); err != nil {
+		t.Fatalf("expected csv export to succeed, got %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("expected valid csv, got %v", err)
+	}
+	if len(records) != 3 || !reflect.DeepEqual(records[0], Fields) {
+		t.Fatalf("expected header and two rows, got %v", records)
+	}
+	row := make(map[string]string)
+	for i, field := range Fields {
+		row[field] = records[1][i]
+	}
+	if row["wpm"] != "72.25" || row["punctuation"] != "true" || row["duration"] != "30" || row["finished_at"] != "2024-06-01T10:30:00Z" {
+		t.Fatalf("unexpected csv row: %v", row)
+	}
+	for i, field := range Fields {
+		if field == "finished_at" && records[2][i] != "" {
+			t.Fatalf("expected zero timestamps to be empty, got %q", records[2][i])
+		}
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(&buf, exportFixture(), FormatJSON); err != nil {
+		t.Fatalf("expected json export to succeed, got %v", err)
+	}
+	var decoded []models.Result
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("expected valid json, got %v", err)
+	}
+	if len(decoded) != 2 || decoded[1].QuoteID != 9 {
+		t.Fatalf("unexpected decoded results: %+v", decoded)
+	}
+
+	buf.Reset()
+	if err := Export(&buf, nil, FormatJSON); err != nil || strings.TrimSpace(buf.String()) != "[]" {
+		t.Fatalf("expected empty json array, got %q (%v)", buf.String(), err)
+	}
+}
+
+func TestExportNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(&buf, exportFixture(), FormatNDJSON); err != nil {
+		t.Fatalf("expected ndjson export to succeed, got %v", err)
+	}
+	lines := 0
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var row map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			t.Fatalf("expected each line to be a json object, got %v", err)
+		}
+		// Every row carries every key, even for values left at zero.
+		if len(row) != len(Fields) {
+			t.Fatalf("expected %d keys on line %d, got %v", len(Fields), lines+1, row)
+		}
+		for _, field := range Fields {
+			if _, ok := row[field]; !ok {
+				t.Fatalf("expected key %q on line %d, got %v", field, lines+1, row)
+			}
+		}
+		lines++
+	}
+	if lines != 2 {
+		t.Fatalf("expected 2 lines, got %d", lines)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if format, err := ParseFormat("CSV"); err != nil || format != FormatCSV {
+		t.Fatalf("expected csv format, got %q (%v)", format, err)
+	}
+	if _, err := ParseFormat("xlsx"); err == nil {
+		t.Fatalf("expected error for unsupported format")
+	}
+}
@@ -0,0 +1,74 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+const dateLayout = "2006-01-02 15:04"
+
+// Headers names the columns produced by Row.
+var Headers = []string{"Date", "Mode", "Setting", "Language", "Speed", "Accuracy", "Consistency", "Raw", "Flags", "Outcome"}
+
+// Row formats a result for tabular listings.
+func Row(result models.Result) []string {
+	unit := UnitOf(result)
+	return []string{
+		result.FinishedAt.Local().Format(dateLayout),
+		string(result.Mode),
+		Setting(result),
+		string(result.Language),
+		fmt.Sprintf("%.1f %s", result.WPM, strings.ToLower(unit)),
+		fmt.Sprintf("%.1f%%", result.Accuracy),
+		fmt.Sprintf("%.0f%%", result.Consistency),
+		fmt.Sprintf("%.1f", result.RawWPM),
+		Flags(result),
+		string(result.Outcome),
+	}
+}
+
+// UnitOf returns the unit a result's speed is in, or WPM when the result does
+// not name one.
+func UnitOf(result models.Result) string {
+	if result.Unit == "" {
+		return "WPM"
+	}
+	return result.Unit
+}
+
+// Setting summarises the mode-specific part of a result's configuration,
+// e.g. "60s", "25 words" or "quote #12".
+func Setting(result models.Result) string {
+	switch result.Mode {
+	case models.TimeMode:
+		return fmt.Sprintf("%ds", result.Duration)
+	case models.WordsMode:
+		return fmt.Sprintf("%d words", result.WordCount)
+	case models.QuoteMode:
+		if result.QuoteID > 0 {
+			return fmt.Sprintf("quote #%d", result.QuoteID)
+		}
+	}
+	return string(result.Mode)
+}
+
+// Flags lists the optional content toggles and the difficulty a result was
+// run with.
+func Flags(result models.Result) string {
+	var flags []string
+	if result.Difficulty != "" && result.Difficulty != models.NormalDifficulty {
+		flags = append(flags, string(result.Difficulty))
+	}
+	if result.Punctuation {
+		flags = append(flags, "punctuation")
+	}
+	if result.Numbers {
+		flags = append(flags, "numbers")
+	}
+	if len(flags) == 0 {
+		return "-"
+	}
+	return strings.Join(flags, ",")
+}
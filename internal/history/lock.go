@@ -0,0 +1,99 @@
+package history
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	lockRetryInterval = 10 * time.Millisecond
+	lockTimeout       = 5 * time.Second
+	// A lock older than this belongs to a process that died mid-write.
+	// Writers hold the lock for milliseconds, and the age stays well under
+	// lockTimeout so a crashed writer only delays the next save.
+	staleLockAge = 2 * time.Second
+)
+
+// lock takes an exclusive lock on path by creating path.lock with O_EXCL,
+// which works the same way on every platform Go supports. The lock file holds
+// a token naming its owner. The returned function releases it.
+func lock(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+
+	token, err := randomSuffix()
+	if err != nil {
+		return nil, fmt.Errorf("history: lock %s: %w", path, err)
+	}
+
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_, writeErr := file.WriteString(token)
+			file.Close()
+			if writeErr != nil {
+				os.Remove(lockPath)
+				return nil, fmt.Errorf("history: lock %s: %w", path, writeErr)
+			}
+			return func() { unlock(lockPath, token) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("history: lock %s: %w", path, err)
+		}
+
+		if breakStale(lockPath) {
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("history: timed out waiting for lock %s", lockPath)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// breakStale removes lockPath when it is older than staleLockAge and reports
+// whether it did. The lock is first renamed aside, which only one waiter can
+// do, and its age is checked again on the renamed file: a lock created after
+// the first check is put back rather than deleted from under its owner.
+func breakStale(lockPath string) bool {
+	if info, err := os.Stat(lockPath); err != nil || time.Since(info.ModTime()) <= staleLockAge {
+		return false
+	}
+	suffix, err := randomSuffix()
+	if err != nil {
+		return false
+	}
+	aside := lockPath + ".stale-" + suffix
+	if err := os.Rename(lockPath, aside); err != nil {
+		return false
+	}
+	defer os.Remove(aside)
+
+	if info, err := os.Stat(aside); err == nil && time.Since(info.ModTime()) <= staleLockAge {
+		// Another waiter broke the stale lock first and this is its
+		// replacement. Linking fails rather than replace a newer lock.
+		os.Link(aside, lockPath)
+		return false
+	}
+	return true
+}
+
+// unlock removes lockPath if it still holds token, so an owner that was too
+// slow and had its lock broken does not release someone else's.
+func unlock(lockPath, token string) {
+	if data, err := os.ReadFile(lockPath); err == nil && string(data) == token {
+		os.Remove(lockPath)
+	}
+}
+
+func randomSuffix() (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(suffix), nil
+}
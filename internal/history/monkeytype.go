@@ -0,0 +1,242 @@
+package history
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+const (
+	// SourceMonkeytype tags results imported from a monkeytype CSV export.
+	SourceMonkeytype = "monkeytype"
+
+	monkeytypeCharsPerWord = 5
+)
+
+// monkeytypeRequired lists the columns an export must have to be imported.
+var monkeytypeRequired = []string{"_id", "wpm", "acc", "mode", "mode2", "language", "timestamp"}
+
+// ImportReport describes the rows of an import that were not turned into
+// results, with a reason for each.
+type ImportReport struct {
+	Skipped []string
+}
+
+// ParseMonkeytype maps the rows of a monkeytype results export onto result
+// records. Rows for modes or languages this app does not have are skipped
+// and listed in the report rather than failing the whole import.
+func ParseMonkeytype(r io.Reader) ([]models.Result, ImportReport, error) {
+	var report ImportReport
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, report, fmt.Errorf("monkeytype: empty file")
+		}
+		return nil, report, fmt.Errorf("monkeytype: read header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	for _, name := range monkeytypeRequired {
+		if _, ok := columns[name]; !ok {
+			return nil, report, fmt.Errorf("monkeytype: missing column %q", name)
+		}
+	}
+
+	var results []models.Result
+	line := 1
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, report, fmt.Errorf("monkeytype: read line %d: %w", line, err)
+		}
+
+		row := monkeytypeRow{columns: columns, record: record}
+		result, err := row.result()
+		if err != nil {
+			report.Skipped = append(report.Skipped, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, report, nil
+}
+
+type monkeytypeRow struct {
+	columns map[string]int
+	record  []string
+}
+
+func (r monkeytypeRow) get(name string) string {
+	index, ok := r.columns[name]
+	if !ok || index >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[index])
+}
+
+func (r monkeytypeRow) float(name string) (float64, error) {
+	value := r.get(name)
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return parsed, nil
+}
+
+func (r monkeytypeRow) result() (models.Result, error) {
+	id := r.get("_id")
+	if id == "" {
+		return models.Result{}, fmt.Errorf("missing _id")
+	}
+
+	result := models.Result{
+		Source:      SourceMonkeytype,
+		ExternalID:  id,
+		Metric:      models.CharacterWPM,
+		Outcome:     models.OutcomeCompleted,
+		Punctuation: r.get("punctuation") == "true",
+		Numbers:     r.get("numbers") == "true",
+	}
+	if r.get("bailedOut") == "true" {
+		result.Outcome = models.OutcomeIncomplete
+	}
+	switch difficulty := models.Difficulty(r.get("difficulty")); difficulty {
+	case models.ExpertDifficulty, models.MasterDifficulty:
+		result.Difficulty = difficulty
+	}
+
+	language, ok := monkeytypeLanguage(r.get("language"))
+	if !ok {
+		return models.Result{}, fmt.Errorf("unsupported language %q", r.get("language"))
+	}
+	result.Language = language
+	scoring := language.Scoring()
+	result.Unit = scoring.Unit
+
+	mode2 := r.get("mode2")
+	switch mode := models.Mode(r.get("mode")); mode {
+	case models.TimeMode:
+		seconds, err := strconv.Atoi(mode2)
+		if err != nil {
+			return models.Result{}, fmt.Errorf("invalid time mode2 %q", mode2)
+		}
+		result.Mode, result.Duration = mode, models.Duration(seconds)
+	case models.WordsMode:
+		count, err := strconv.Atoi(mode2)
+		if err != nil {
+			return models.Result{}, fmt.Errorf("invalid words mode2 %q", mode2)
+		}
+		result.Mode, result.WordCount = mode, models.WordCount(count)
+	case models.QuoteMode:
+		// Monkeytype quote ids refer to its own collection, so they are not
+		// kept; the quote is only identified by language.
+		result.Mode = mode
+	case models.ZenMode:
+		result.Mode = mode
+	default:
+		return models.Result{}, fmt.Errorf("unsupported mode %q", mode)
+	}
+
+	var err error
+	if result.WPM, err = r.float("wpm"); err != nil {
+		return models.Result{}, err
+	}
+	if result.Accuracy, err = r.float("acc"); err != nil {
+		return models.Result{}, err
+	}
+	if result.RawWPM, err = r.float("rawWpm"); err != nil {
+		return models.Result{}, err
+	}
+	if result.Consistency, err = r.float("consistency"); err != nil {
+		return models.Result{}, err
+	}
+	if result.ElapsedSeconds, err = r.float("testDuration"); err != nil {
+		return models.Result{}, err
+	}
+
+	// charStats is "correct;incorrect;extra;missed".
+	if stats := r.get("charStats"); stats != "" {
+		counts := strings.Split(stats, ";")
+		targets := []*int{&result.CorrectChars, &result.IncorrectChars, &result.ExtraChars, &result.MissedChars}
+		for i := 0; i < len(counts) && i < len(targets); i++ {
+			if *targets[i], err = strconv.Atoi(strings.TrimSpace(counts[i])); err != nil {
+				return models.Result{}, fmt.Errorf("invalid charStats %q", stats)
+			}
+		}
+	}
+
+	// Monkeytype's wpm counts correct characters over five in every
+	// language, so it is rescaled to the language's own scoring, e.g. CPM
+	// for Chinese. Net and CPM follow this app's formulas.
+	result.CPM = result.WPM * monkeytypeCharsPerWord
+	result.WPM = result.CPM / scoring.CharsPerWord
+	result.RawWPM = result.RawWPM * monkeytypeCharsPerWord / scoring.CharsPerWord
+	if minutes := result.ElapsedSeconds / 60; minutes > 0 {
+		mistakes := float64(result.IncorrectChars + result.ExtraChars)
+		result.NetWPM = math.Max(0, result.RawWPM-mistakes/minutes)
+	}
+
+	timestamp, err := strconv.ParseInt(r.get("timestamp"), 10, 64)
+	if err != nil {
+		return models.Result{}, fmt.Errorf("invalid timestamp %q", r.get("timestamp"))
+	}
+	result.FinishedAt = time.UnixMilli(timestamp).UTC()
+	result.StartedAt = result.FinishedAt.Add(-time.Duration(result.ElapsedSeconds * float64(time.Second)))
+	result.ID = SourceMonkeytype + "-" + id
+
+	return result, nil
+}
+
+// monkeytypeLanguage maps monkeytype's language names, including its sized
+// variants such as "english_1k", onto the languages this app supports.
+func monkeytypeLanguage(name string) (models.Language, bool) {
+	if language, ok := models.NormalizeLanguage(name); ok {
+		return language, true
+	}
+	if base, _, found := strings.Cut(name, "_"); found && base != "code" {
+		return models.NormalizeLanguage(base)
+	}
+	return "", false
+}
+
+// Dedupe drops imported results already present in existing, or repeated
+// within imported, by source and external id.
+func Dedupe(existing, imported []models.Result) (fresh []models.Result, duplicates int) {
+	type externalKey struct{ source, id string }
+	seen := make(map[externalKey]bool)
+	for _, result := range existing {
+		if result.ExternalID != "" {
+			seen[externalKey{result.Source, result.ExternalID}] = true
+		}
+	}
+	for _, result := range imported {
+		key := externalKey{result.Source, result.ExternalID}
+		if seen[key] {
+			duplicates++
+			continue
+		}
+		seen[key] = true
+		fresh = append(fresh, result)
+	}
+	return fresh, duplicates
+}
@@ -0,0 +1,111 @@
+package history
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+const monkeytypeCSV = `_id,isPb,wpm,acc,rawWpm,consistency,charStats,mode,mode2,quoteLength,restartCount,testDuration,afkDuration,incompleteTestSeconds,lazyMode,blindMode,bailedOut,tags,funbox,language,punctuation,numbers,difficulty,timestamp
+a1,true,85.2,97.5,90,81.3,213;4;1;0,time,30,-1,0,30,0,0,false,false,false,,none,english,false,false,normal,1700000000000
+a2,false,60,95,62,70,100;3;0;2,words,25,-1,0,20,0,0,false,false,false,,none,english_1k,true,true,expert,1700000100000
+a3,false,70,99,71,75,300;1;0;0,quote,1234,2,1,55,0,0,false,false,true,,none,spanish,false,false,normal,1700000200000
+a4,false,50,90,55,60,100;5;0;0,zen,zen,-1,0,20,0,0,false,false,false,,none,english,false,false,normal,1700000300000
+a5,false,50,90,55,60,100;5;0;0,time,15,-1,0,15,0,0,false,false,false,,none,klingon,false,false,normal,1700000400000
+`
+
+func TestParseMonkeytype(t *testing.T) {
+	results, report, err := ParseMonkeytype(strings.NewReader(monkeytypeCSV))
+	if err != nil {
+		t.Fatalf("expected import to succeed, got %v", err)
+	}
+	if len(results) != 4 || len(report.Skipped) != 1 {
+		t.Fatalf("expected 4 results and 1 skipped row, got %d and %v", len(results), report.Skipped)
+	}
+
+	timed := results[0]
+	if timed.ID != "monkeytype-a1" || timed.Source != SourceMonkeytype || timed.ExternalID != "a1" {
+		t.Fatalf("unexpected identity: %+v", timed)
+	}
+	if timed.Mode != models.TimeMode || timed.Duration != 30 || timed.Language != models.English || timed.Unit != "WPM" {
+		t.Fatalf("unexpected configuration: %+v", timed)
+	}
+	if timed.WPM != 85.2 || timed.Accuracy != 97.5 || timed.RawWPM != 90 || timed.Consistency != 81.3 {
+		t.Fatalf("unexpected scores: %+v", timed)
+	}
+	if timed.CorrectChars != 213 || timed.IncorrectChars != 4 || timed.ExtraChars != 1 {
+		t.Fatalf("unexpected char stats: %+v", timed)
+	}
+	if !timed.FinishedAt.Equal(time.UnixMilli(1700000000000)) || timed.ElapsedSeconds != 30 {
+		t.Fatalf("unexpected timing: %+v", timed)
+	}
+	if timed.NetWPM != 80 {
+		t.Fatalf("expected net WPM of 80, got %v", timed.NetWPM)
+	}
+
+	words := results[1]
+	if words.Mode != models.WordsMode || words.WordCount != 25 || words.Language != models.English || !words.Punctuation || !words.Numbers {
+		t.Fatalf("unexpected words result: %+v", words)
+	}
+	if words.Difficulty != models.ExpertDifficulty || timed.Difficulty != "" {
+		t.Fatalf("expected only non-normal difficulties to be kept, got %q and %q", words.Difficulty, timed.Difficulty)
+	}
+
+	quote := results[2]
+	if quote.Mode != models.QuoteMode || quote.QuoteID != 0 || quote.Outcome != models.OutcomeIncomplete {
+		t.Fatalf("unexpected quote result: %+v", quote)
+	}
+
+	if zen := results[3]; zen.Mode != models.ZenMode {
+		t.Fatalf("unexpected zen result: %+v", zen)
+	}
+}
+
+func TestParseMonkeytypeScoresChineseByCharacter(t *testing.T) {
+	csv := strings.Join([]string{
+		strings.SplitN(monkeytypeCSV, "\n", 2)[0],
+		"c1,false,20,96,24,70,200;8;0;0,time,60,-1,0,60,0,0,false,false,false,,none,chinese_simplified,false,false,normal,1700000500000",
+	}, "\n")
+	results, _, err := ParseMonkeytype(strings.NewReader(csv))
+	if err != nil || len(results) != 1 {
+		t.Fatalf("expected one imported result, got %v, %v", results, err)
+	}
+
+	chinese := results[0]
+	if chinese.Language != models.Chinese || chinese.Unit != "CPM" {
+		t.Fatalf("expected a Chinese result scored in CPM, got %+v", chinese)
+	}
+	if chinese.WPM != 100 || chinese.RawWPM != 120 || chinese.CPM != 100 {
+		t.Fatalf("expected speeds in characters per minute, got speed %v, raw %v, CPM %v", chinese.WPM, chinese.RawWPM, chinese.CPM)
+	}
+	if chinese.NetWPM != 112 {
+		t.Fatalf("expected net speed of 112, got %v", chinese.NetWPM)
+	}
+}
+
+func TestParseMonkeytypeRequiresColumns(t *testing.T) {
+	if _, _, err := ParseMonkeytype(strings.NewReader("_id,wpm\nx,1\n")); err == nil || !strings.Contains(err.Error(), "missing column") {
+		t.Fatalf("expected missing column error, got %v", err)
+	}
+	if _, _, err := ParseMonkeytype(strings.NewReader("")); err == nil {
+		t.Fatalf("expected error for an empty file")
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	existing := []models.Result{
+		{ID: "local"},
+		{Source: SourceMonkeytype, ExternalID: "a1"},
+	}
+	imported := []models.Result{
+		{Source: SourceMonkeytype, ExternalID: "a1"},
+		{Source: SourceMonkeytype, ExternalID: "a2"},
+		{Source: SourceMonkeytype, ExternalID: "a2"},
+	}
+	fresh, duplicates := Dedupe(existing, imported)
+	if len(fresh) != 1 || fresh[0].ExternalID != "a2" || duplicates != 2 {
+		t.Fatalf("expected only a2 to be fresh, got %+v with %d duplicates", fresh, duplicates)
+	}
+}
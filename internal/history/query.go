@@ -0,0 +1,102 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+// Filter narrows a history down. Zero values match everything; Punctuation
+// and Numbers are pointers so "either" can be told apart from "off".
+type Filter struct {
+	Mode        models.Mode
+	Language    models.Language
+	Since       time.Time
+	Until       time.Time
+	Punctuation *bool
+	Numbers     *bool
+	// QuoteID only matches results for this quote; quote ids are numbered
+	// per language.
+	QuoteID int
+}
+
+func (f Filter) Match(result models.Result) bool {
+	if f.Mode != "" && result.Mode != f.Mode {
+		return false
+	}
+	if f.Language != "" && result.Language != f.Language {
+		return false
+	}
+	if !f.Since.IsZero() && result.FinishedAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !result.FinishedAt.Before(f.Until) {
+		return false
+	}
+	if f.Punctuation != nil && result.Punctuation != *f.Punctuation {
+		return false
+	}
+	if f.Numbers != nil && result.Numbers != *f.Numbers {
+		return false
+	}
+	if f.QuoteID != 0 && result.QuoteID != f.QuoteID {
+		return false
+	}
+	return true
+}
+
+// SortField orders query results, always best or newest first.
+type SortField string
+
+const (
+	SortByDate     SortField = "date"
+	SortByWPM      SortField = "wpm"
+	SortByAccuracy SortField = "accuracy"
+)
+
+func ParseSortField(value string) (SortField, error) {
+	switch field := SortField(strings.ToLower(strings.TrimSpace(value))); field {
+	case SortByDate, SortByWPM, SortByAccuracy:
+		return field, nil
+	default:
+		return "", fmt.Errorf("unsupported sort field %q. Supported fields: '%s', '%s', '%s'", value, SortByDate, SortByWPM, SortByAccuracy)
+	}
+}
+
+// Query filters, sorts and truncates results. A limit of zero keeps them all.
+func Query(results []models.Result, filter Filter, field SortField, limit int) []models.Result {
+	matched := make([]models.Result, 0, len(results))
+	for _, result := range results {
+		if filter.Match(result) {
+			matched = append(matched, result)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		switch field {
+		case SortByWPM:
+			// Speeds on different scales do not compare, so each scale
+			// is ranked on its own (see Scale.before).
+			if sa, sb := ScaleOf(a), ScaleOf(b); sa != sb {
+				return sa.before(sb)
+			}
+			if a.WPM != b.WPM {
+				return a.WPM > b.WPM
+			}
+		case SortByAccuracy:
+			if a.Accuracy != b.Accuracy {
+				return a.Accuracy > b.Accuracy
+			}
+		}
+		return a.FinishedAt.After(b.FinishedAt)
+	})
+
+	if limit > 0 && len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched
+}
@@ -0,0 +1,123 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+func queryFixture() []models.Result {
+	base := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	return []models.Result{
+		{ID: "a", Mode: models.TimeMode, Language: models.English, Duration: 60, WPM: 80, Accuracy: 95, FinishedAt: base},
+		{ID: "b", Mode: models.WordsMode, Language: models.English, WordCount: 25, WPM: 90, Accuracy: 90, Punctuation: true, FinishedAt: base.Add(24 * time.Hour)},
+		{ID: "c", Mode: models.TimeMode, Language: models.Spanish, Duration: 30, WPM: 70, Accuracy: 99, Numbers: true, FinishedAt: base.Add(48 * time.Hour)},
+	}
+}
+
+func ids(results []models.Result) string {
+	var out string
+	for _, result := range results {
+		out += result.ID
+	}
+	return out
+}
+
+func TestQuerySortsNewestFirstByDefault(t *testing.T) {
+	if got := ids(Query(queryFixture(), Filter{}, SortByDate, 0)); got != "cba" {
+		t.Fatalf("expected newest first, got %q", got)
+	}
+}
+
+func TestQuerySortsByWPMAndAccuracy(t *testing.T) {
+	if got := ids(Query(queryFixture(), Filter{}, SortByWPM, 0)); got != "bac" {
+		t.Fatalf("expected fastest first, got %q", got)
+	}
+	if got := ids(Query(queryFixture(), Filter{}, SortByAccuracy, 0)); got != "cab" {
+		t.Fatalf("expected most accurate first, got %q", got)
+	}
+}
+
+func TestQueryAppliesLimit(t *testing.T) {
+	if got := ids(Query(queryFixture(), Filter{}, SortByWPM, 2)); got != "ba" {
+		t.Fatalf("expected the top two results, got %q", got)
+	}
+}
+
+func TestQueryFilters(t *testing.T) {
+	base := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	yes, no := true, false
+	tests := []struct {
+		name   string
+		filter Filter
+		want   string
+	}{
+		{"mode", Filter{Mode: models.TimeMode}, "ca"},
+		{"language", Filter{Language: models.English}, "ba"},
+		{"since", Filter{Since: base.Add(24 * time.Hour)}, "cb"},
+		{"until is exclusive", Filter{Until: base.Add(24 * time.Hour)}, "a"},
+		{"punctuation on", Filter{Punctuation: &yes}, "b"},
+		{"numbers off", Filter{Numbers: &no}, "ba"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ids(Query(queryFixture(), tt.filter, SortByDate, 0)); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestQueryFiltersByQuoteID(t *testing.T) {
+	results := []models.Result{
+		{ID: "a", Mode: models.QuoteMode, Language: models.English, QuoteID: 42, WPM: 60},
+		{ID: "b", Mode: models.QuoteMode, Language: models.English, QuoteID: 7, WPM: 90},
+		{ID: "c", Mode: models.QuoteMode, Language: models.English, QuoteID: 42, WPM: 75},
+	}
+	if got := ids(Query(results, Filter{QuoteID: 42}, SortByWPM, 1)); got != "c" {
+		t.Fatalf("expected the best result for quote 42, got %q", got)
+	}
+}
+
+func TestParseSortField(t *testing.T) {
+	field, err := ParseSortField(" WPM ")
+	if err != nil || field != SortByWPM {
+		t.Fatalf("expected wpm sort field, got %q (%v)", field, err)
+	}
+	if _, err := ParseSortField("speed"); err == nil {
+		t.Fatalf("expected error for unsupported sort field")
+	}
+}
+
+func TestSettingAndFlags(t *testing.T) {
+	results := queryFixture()
+	if got := Setting(results[0]); got != "60s" {
+		t.Fatalf("expected time setting, got %q", got)
+	}
+	if got := Setting(results[1]); got != "25 words" {
+		t.Fatalf("expected words setting, got %q", got)
+	}
+	if got := Setting(models.Result{Mode: models.QuoteMode, QuoteID: 12}); got != "quote #12" {
+		t.Fatalf("expected quote setting, got %q", got)
+	}
+	if got := Flags(results[0]); got != "-" {
+		t.Fatalf("expected no flags, got %q", got)
+	}
+	if got := Flags(models.Result{Punctuation: true, Numbers: true}); got != "punctuation,numbers" {
+		t.Fatalf("expected both flags, got %q", got)
+	}
+}
+
+func TestQuerySortsSpeedsWithinScales(t *testing.T) {
+	results := []models.Result{
+		{ID: "a", Unit: "CPM", WPM: 150},
+		{ID: "b", WPM: 60},
+		{ID: "c", Unit: "WPM", WPM: 80},
+		{ID: "d", Unit: "CPM", WPM: 200},
+		{ID: "e", Unit: "WPM", Metric: models.WordWPM, WPM: 90},
+	}
+	if got := ids(Query(results, Filter{}, SortByWPM, 0)); got != "cbeda" {
+		t.Fatalf("expected each scale ranked on its own, got %q", got)
+	}
+}
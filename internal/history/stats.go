@@ -0,0 +1,250 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+// Summary aggregates the speed and accuracy of a set of results.
+type Summary struct {
+	Count           int
+	AverageWPM      float64
+	BestWPM         float64
+	AverageAccuracy float64
+}
+
+// Summarize averages the results that count towards scores (see Counts).
+// Speeds are only comparable within a scale (see ByScale).
+func Summarize(results []models.Result) Summary {
+	var summary Summary
+	for _, result := range results {
+		if !Counts(result) {
+			continue
+		}
+		summary.Count++
+		summary.AverageWPM += result.WPM
+		summary.AverageAccuracy += result.Accuracy
+		if result.WPM > summary.BestWPM {
+			summary.BestWPM = result.WPM
+		}
+	}
+	if summary.Count > 0 {
+		summary.AverageWPM /= float64(summary.Count)
+		summary.AverageAccuracy /= float64(summary.Count)
+	}
+	return summary
+}
+
+// Latest returns the n most recently finished results that count towards
+// scores, newest first.
+func Latest(results []models.Result, n int) []models.Result {
+	counted := make([]models.Result, 0, len(results))
+	for _, result := range results {
+		if Counts(result) {
+			counted = append(counted, result)
+		}
+	}
+	return Query(counted, Filter{}, SortByDate, n)
+}
+
+// Scale is what a speed is measured in: its unit and the WPM metric that
+// scored it. Speeds on different scales do not compare.
+type Scale struct {
+	Unit   string
+	Metric models.WPMMetric
+}
+
+// ScaleOf returns the scale of a result's speed.
+func ScaleOf(result models.Result) Scale {
+	return Scale{Unit: UnitOf(result), Metric: metricOrDefault(result.Metric)}
+}
+
+// String names the scale, e.g. "CPM" or "WPM (words metric)".
+func (s Scale) String() string {
+	if s.Metric == models.CharacterWPM {
+		return s.Unit
+	}
+	return fmt.Sprintf("%s (%s metric)", s.Unit, s.Metric)
+}
+
+// before orders scales WPM first, then by unit, with the default character
+// metric ahead of the word metric.
+func (s Scale) before(other Scale) bool {
+	if s.Unit != other.Unit {
+		if s.Unit == "WPM" || other.Unit == "WPM" {
+			return s.Unit == "WPM"
+		}
+		return s.Unit < other.Unit
+	}
+	if s.Metric != other.Metric {
+		return s.Metric == models.CharacterWPM
+	}
+	return false
+}
+
+// ScaleResults holds the results whose speeds share a scale.
+type ScaleResults struct {
+	Scale
+	Results []models.Result
+}
+
+// ByScale splits results by the scale of their speed, e.g. WPM, the CPM of
+// Chinese tests and WPM scored by words, most results first. Order within
+// each scale is kept.
+func ByScale(results []models.Result) []ScaleResults {
+	var scales []ScaleResults
+	index := make(map[Scale]int)
+	for _, result := range results {
+		scale := ScaleOf(result)
+		i, ok := index[scale]
+		if !ok {
+			i = len(scales)
+			index[scale] = i
+			scales = append(scales, ScaleResults{Scale: scale})
+		}
+		scales[i].Results = append(scales[i].Results, result)
+	}
+	sort.SliceStable(scales, func(i, j int) bool {
+		if len(scales[i].Results) != len(scales[j].Results) {
+			return len(scales[i].Results) > len(scales[j].Results)
+		}
+		return scales[i].Scale.before(scales[j].Scale)
+	})
+	return scales
+}
+
+// Group is a Summary for one value of a breakdown, e.g. a language.
+type Group struct {
+	Name string
+	Summary
+}
+
+// ByLanguage summarises results per language, most played first.
+func ByLanguage(results []models.Result) []Group {
+	return groupBy(results, func(result models.Result) string { return string(result.Language) })
+}
+
+// ByMode summarises results per mode, most played first.
+func ByMode(results []models.Result) []Group {
+	return groupBy(results, func(result models.Result) string { return string(result.Mode) })
+}
+
+func groupBy(results []models.Result, name func(models.Result) string) []Group {
+	buckets := make(map[string][]models.Result)
+	for _, result := range results {
+		key := name(result)
+		buckets[key] = append(buckets[key], result)
+	}
+
+	groups := make([]Group, 0, len(buckets))
+	for key, bucket := range buckets {
+		if summary := Summarize(bucket); summary.Count > 0 {
+			groups = append(groups, Group{Name: key, Summary: summary})
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+// TotalTime adds up the time spent typing across every result, including
+// tests that were ended early.
+func TotalTime(results []models.Result) time.Duration {
+	var total float64
+	for _, result := range results {
+		total += result.ElapsedSeconds
+	}
+	return time.Duration(total * float64(time.Second))
+}
+
+// Streaks counts consecutive local calendar days with at least one test. The
+// current streak is still alive if the last test was today or yesterday.
+func Streaks(results []models.Result, now time.Time) (current, longest int) {
+	days := make(map[time.Time]bool)
+	for _, result := range results {
+		if !result.FinishedAt.IsZero() {
+			days[startOfDay(result.FinishedAt)] = true
+		}
+	}
+	if len(days) == 0 {
+		return 0, 0
+	}
+
+	sorted := make([]time.Time, 0, len(days))
+	for day := range days {
+		sorted = append(sorted, day)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	run := 0
+	for i, day := range sorted {
+		if i > 0 && startOfDay(sorted[i-1].AddDate(0, 0, 1)).Equal(day) {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+
+	today := startOfDay(now)
+	last := sorted[len(sorted)-1]
+	if last.Equal(today) || last.Equal(today.AddDate(0, 0, -1)) {
+		current = run
+	}
+	return current, longest
+}
+
+// Week summarises the results finished in the week starting on Start.
+type Week struct {
+	Start time.Time
+	Summary
+}
+
+// WeeklyTrend summarises the last n weeks up to and including the one
+// containing now, oldest first. Weeks start on Monday in local time and
+// weeks without results are kept so gaps stay visible.
+func WeeklyTrend(results []models.Result, now time.Time, n int) []Week {
+	if n <= 0 {
+		return nil
+	}
+
+	current := startOfWeek(now)
+	weeks := make([]Week, n)
+	buckets := make([][]models.Result, n)
+	for i := range weeks {
+		weeks[i].Start = current.AddDate(0, 0, -7*(n-1-i))
+	}
+	for _, result := range results {
+		start := startOfWeek(result.FinishedAt)
+		for i := range weeks {
+			if weeks[i].Start.Equal(start) {
+				buckets[i] = append(buckets[i], result)
+				break
+			}
+		}
+	}
+	for i := range weeks {
+		weeks[i].Summary = Summarize(buckets[i])
+	}
+	return weeks
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+func startOfWeek(t time.Time) time.Time {
+	day := startOfDay(t)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,113 @@
+package history
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+func TestSummarizeSkipsIncomplete(t *testing.T) {
+	summary := Summarize([]models.Result{
+		{WPM: 60, Accuracy: 90, Outcome: models.OutcomeCompleted},
+		{WPM: 80, Accuracy: 100, Outcome: models.OutcomeCompleted},
+		{WPM: 200, Accuracy: 100, Outcome: models.OutcomeIncomplete},
+	})
+	if summary.Count != 2 || summary.AverageWPM != 70 || summary.BestWPM != 80 || summary.AverageAccuracy != 95 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+}
+
+func TestLatestKeepsNewestCountedResults(t *testing.T) {
+	base := time.Date(2024, 5, 1, 9, 0, 0, 0, time.Local)
+	results := []models.Result{
+		{ID: "a", Outcome: models.OutcomeCompleted, FinishedAt: base},
+		{ID: "b", Outcome: models.OutcomeIncomplete, FinishedAt: base.Add(time.Hour)},
+		{ID: "c", Outcome: models.OutcomeCompleted, FinishedAt: base.Add(2 * time.Hour)},
+		{ID: "d", Outcome: models.OutcomeCompleted, FinishedAt: base.Add(3 * time.Hour)},
+	}
+	if got := ids(Latest(results, 2)); got != "dc" {
+		t.Fatalf("expected the two newest completed results, got %q", got)
+	}
+}
+
+func TestGroupsOrderedByCount(t *testing.T) {
+	results := []models.Result{
+		{Mode: models.TimeMode, Language: models.English, WPM: 50, Outcome: models.OutcomeCompleted},
+		{Mode: models.WordsMode, Language: models.Spanish, WPM: 40, Outcome: models.OutcomeCompleted},
+		{Mode: models.WordsMode, Language: models.English, WPM: 60, Outcome: models.OutcomeCompleted},
+	}
+	modes := ByMode(results)
+	if len(modes) != 2 || modes[0].Name != "words" || modes[0].Count != 2 || modes[0].AverageWPM != 50 {
+		t.Fatalf("unexpected mode breakdown: %+v", modes)
+	}
+	languages := ByLanguage(results)
+	if len(languages) != 2 || languages[0].Name != "english" || languages[0].BestWPM != 60 {
+		t.Fatalf("unexpected language breakdown: %+v", languages)
+	}
+}
+
+func TestTotalTime(t *testing.T) {
+	total := TotalTime([]models.Result{{ElapsedSeconds: 30}, {ElapsedSeconds: 15.5}})
+	if total != 45500*time.Millisecond {
+		t.Fatalf("expected 45.5s, got %v", total)
+	}
+}
+
+func TestStreaks(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 20, 0, 0, 0, time.Local) }
+	results := []models.Result{
+		{FinishedAt: day(1)}, {FinishedAt: day(2)}, {FinishedAt: day(3)},
+		{FinishedAt: day(6)}, {FinishedAt: day(7)}, {FinishedAt: day(7).Add(time.Hour)},
+	}
+
+	current, longest := Streaks(results, day(8))
+	if current != 2 || longest != 3 {
+		t.Fatalf("expected current 2 and longest 3, got %d and %d", current, longest)
+	}
+	if current, _ := Streaks(results, day(9)); current != 0 {
+		t.Fatalf("expected streak to lapse after a missed day, got %d", current)
+	}
+	if current, longest := Streaks(nil, day(9)); current != 0 || longest != 0 {
+		t.Fatalf("expected no streak without results")
+	}
+}
+
+func TestWeeklyTrend(t *testing.T) {
+	// 2024-05-15 is a Wednesday; its week starts on Monday 2024-05-13.
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.Local)
+	results := []models.Result{
+		{WPM: 50, Outcome: models.OutcomeCompleted, FinishedAt: time.Date(2024, 5, 6, 8, 0, 0, 0, time.Local)},
+		{WPM: 70, Outcome: models.OutcomeCompleted, FinishedAt: time.Date(2024, 5, 13, 0, 30, 0, 0, time.Local)},
+		{WPM: 90, Outcome: models.OutcomeCompleted, FinishedAt: now},
+		{WPM: 99, Outcome: models.OutcomeCompleted, FinishedAt: time.Date(2024, 4, 1, 8, 0, 0, 0, time.Local)},
+	}
+
+	weeks := WeeklyTrend(results, now, 3)
+	if len(weeks) != 3 {
+		t.Fatalf("expected 3 weeks, got %d", len(weeks))
+	}
+	if want := time.Date(2024, 5, 13, 0, 0, 0, 0, time.Local); !weeks[2].Start.Equal(want) {
+		t.Fatalf("expected current week to start %v, got %v", want, weeks[2].Start)
+	}
+	if weeks[0].Count != 0 || weeks[1].AverageWPM != 50 || math.Abs(weeks[2].AverageWPM-80) > 1e-9 {
+		t.Fatalf("unexpected trend: %+v", weeks)
+	}
+}
+
+func TestByScaleKeepsSpeedsApart(t *testing.T) {
+	results := []models.Result{
+		{ID: "a", Unit: "CPM", WPM: 200, Outcome: models.OutcomeCompleted},
+		{ID: "b", WPM: 60, Outcome: models.OutcomeCompleted},
+		{ID: "c", Unit: "WPM", Metric: models.CharacterWPM, WPM: 80, Outcome: models.OutcomeCompleted},
+		{ID: "d", Unit: "WPM", Metric: models.WordWPM, WPM: 120, Outcome: models.OutcomeCompleted},
+	}
+	scales := ByScale(results)
+	if len(scales) != 3 || ids(scales[0].Results) != "bc" || scales[1].String() != "WPM (words metric)" || scales[2].String() != "CPM" {
+		t.Fatalf("unexpected scales: %+v", scales)
+	}
+	if summary := Summarize(scales[0].Results); summary.AverageWPM != 70 || summary.BestWPM != 80 {
+		t.Fatalf("expected other scales to be left out of the WPM summary, got %+v", summary)
+	}
+}
@@ -0,0 +1,229 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+const (
+	appDirName      = "typing-test-tui"
+	historyFileName = "history.jsonl"
+)
+
+// Store appends results to a newline-delimited JSON file. Every write and
+// read happens under a lock file so several running instances can share it.
+type Store struct {
+	path string
+}
+
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Default returns the store at DefaultPath.
+func Default() (*Store, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(path), nil
+}
+
+// DefaultPath resolves the history file inside $XDG_DATA_HOME, falling back
+// to ~/.local/share when the variable is unset.
+func DefaultPath() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("history: resolve data directory: %w", err)
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, appDirName, historyFileName), nil
+}
+
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Append writes result as a single line, assigning an ID when it has none.
+func (s *Store) Append(result models.Result) error {
+	return s.AppendAll([]models.Result{result})
+}
+
+// AppendAll writes results in order under a single lock, assigning IDs to
+// those without one.
+func (s *Store) AppendAll(results []models.Result) error {
+	if len(results) == 0 {
+		return nil
+	}
+	lines, err := encode(results)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return s.write(lines)
+}
+
+// Import appends the results that are not stored yet (see Dedupe) and
+// returns them. The history is read and written under a single lock, so two
+// imports running at once cannot both add the same result.
+func (s *Store) Import(results []models.Result) (fresh []models.Result, duplicates int, err error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer unlock()
+
+	data, err := s.read()
+	if err != nil {
+		return nil, 0, err
+	}
+	existing, err := s.decode(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	fresh, duplicates = Dedupe(existing, results)
+	if len(fresh) == 0 {
+		return nil, duplicates, nil
+	}
+	lines, err := encode(fresh)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.write(lines); err != nil {
+		return nil, 0, err
+	}
+	return fresh, duplicates, nil
+}
+
+// Load returns every stored result in the order it was written. A missing
+// file is an empty history, and lines that cannot be decoded (for example a
+// write cut short by a crash) are skipped.
+func (s *Store) Load() ([]models.Result, error) {
+	unlock, err := lock(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// Only reading needs the lock; decoding a large history must not keep
+	// writers waiting long enough to take the lock for stale.
+	data, err := s.read()
+	unlock()
+	if err != nil {
+		return nil, err
+	}
+	return s.decode(data)
+}
+
+// lock creates the history's directory and takes the lock for writing.
+func (s *Store) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return nil, fmt.Errorf("history: create directory: %w", err)
+	}
+	return lock(s.path)
+}
+
+// read returns the history file's contents, or nothing when it is missing.
+// The caller holds the lock.
+func (s *Store) read() ([]byte, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("history: read %s: %w", s.path, err)
+	}
+	return data, nil
+}
+
+// write appends encoded lines to the history file. The caller holds the lock.
+func (s *Store) write(lines []byte) error {
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("history: open %s: %w", s.path, err)
+	}
+	if _, err := file.Write(lines); err != nil {
+		file.Close()
+		return fmt.Errorf("history: write %s: %w", s.path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("history: close %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// decode parses the lines of a history file, skipping those that cannot be
+// decoded.
+func (s *Store) decode(data []byte) ([]models.Result, error) {
+	var results []models.Result
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var result models.Result
+		if err := json.Unmarshal(line, &result); err != nil {
+			continue
+		}
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("history: scan %s: %w", s.path, err)
+	}
+	return results, nil
+}
+
+// encode turns results into history file lines, assigning IDs to those
+// without one.
+func encode(results []models.Result) ([]byte, error) {
+	var lines []byte
+	for _, result := range results {
+		if result.ID == "" {
+			id, err := newID(result.FinishedAt)
+			if err != nil {
+				return nil, err
+			}
+			result.ID = id
+		}
+
+		line, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("history: encode result: %w", err)
+		}
+		lines = append(lines, line...)
+		lines = append(lines, '\n')
+	}
+	return lines, nil
+}
+
+func newID(at time.Time) (string, error) {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("history: generate id: %w", err)
+	}
+	return fmt.Sprintf("%x-%s", at.UnixMilli(), hex.EncodeToString(suffix)), nil
+}
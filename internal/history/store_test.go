@@ -0,0 +1,199 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+func TestStoreAppendAndLoad(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "nested", historyFileName))
+
+	first := models.Result{Mode: models.TimeMode, Language: models.English, WPM: 72.5, FinishedAt: time.Now()}
+	second := models.Result{ID: "fixed", Mode: models.QuoteMode, QuoteID: 7}
+	if err := store.Append(first); err != nil {
+		t.Fatalf("expected append to succeed, got %v", err)
+	}
+	if err := store.Append(second); err != nil {
+		t.Fatalf("expected append to succeed, got %v", err)
+	}
+
+	results, err := store.Load()
+	if err != nil {
+		t.Fatalf("expected load to succeed, got %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].ID == "" || results[0].WPM != 72.5 {
+		t.Fatalf("expected first result to round-trip with an id, got %+v", results[0])
+	}
+	if results[1].ID != "fixed" || results[1].QuoteID != 7 {
+		t.Fatalf("expected existing id to be kept, got %+v", results[1])
+	}
+	if _, err := os.Stat(store.Path() + ".lock"); !os.IsNotExist(err) {
+		t.Fatalf("expected lock file to be released")
+	}
+}
+
+func TestStoreLoadMissingFile(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "missing", historyFileName))
+	results, err := store.Load()
+	if err != nil || len(results) != 0 {
+		t.Fatalf("expected empty history for a missing file, got %v, %v", results, err)
+	}
+}
+
+func TestStoreSkipsCorruptLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), historyFileName)
+	content := "{\"id\":\"a\",\"wpm\":50}\n{\"id\":\"b\",\"wp\n\n{\"id\":\"c\",\"wpm\":60}\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := NewStore(path).Load()
+	if err != nil {
+		t.Fatalf("expected load to succeed, got %v", err)
+	}
+	if len(results) != 2 || results[0].ID != "a" || results[1].ID != "c" {
+		t.Fatalf("expected corrupt line to be skipped, got %+v", results)
+	}
+}
+
+func TestStoreConcurrentAppends(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), historyFileName))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := store.Append(models.Result{WPM: float64(i)}); err != nil {
+				t.Errorf("append %d failed: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	results, err := store.Load()
+	if err != nil {
+		t.Fatalf("expected load to succeed, got %v", err)
+	}
+	if len(results) != 20 {
+		t.Fatalf("expected all 20 results to be stored intact, got %d", len(results))
+	}
+}
+
+func TestDefaultPathUsesXDGDataHome(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dir)
+
+	path, err := DefaultPath()
+	if err != nil {
+		t.Fatalf("expected default path, got %v", err)
+	}
+	if expected := filepath.Join(dir, appDirName, historyFileName); path != expected {
+		t.Fatalf("expected %q, got %q", expected, path)
+	}
+}
+
+func TestStoreBreaksStaleLockBeforeTimingOut(t *testing.T) {
+	if staleLockAge >= lockTimeout {
+		t.Fatalf("expected stale locks (%s) to be broken before waiting times out (%s)", staleLockAge, lockTimeout)
+	}
+
+	store := NewStore(filepath.Join(t.TempDir(), historyFileName))
+	lockPath := store.Path() + ".lock"
+	if err := os.WriteFile(lockPath, nil, 0o644); err != nil {
+		t.Fatalf("expected lock file to be created, got %v", err)
+	}
+	crashed := time.Now().Add(-staleLockAge - time.Second)
+	if err := os.Chtimes(lockPath, crashed, crashed); err != nil {
+		t.Fatalf("expected lock file to be aged, got %v", err)
+	}
+
+	if err := store.Append(models.Result{Mode: models.TimeMode}); err != nil {
+		t.Fatalf("expected a lock left by a crashed writer to be broken, got %v", err)
+	}
+}
+
+func TestStoreConcurrentWritersBreakAStaleLockOnce(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), historyFileName))
+	lockPath := store.Path() + ".lock"
+	if err := os.WriteFile(lockPath, []byte("crashed"), 0o644); err != nil {
+		t.Fatalf("expected lock file to be created, got %v", err)
+	}
+	crashed := time.Now().Add(-staleLockAge - time.Second)
+	if err := os.Chtimes(lockPath, crashed, crashed); err != nil {
+		t.Fatalf("expected lock file to be aged, got %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := store.Append(models.Result{WPM: float64(i)}); err != nil {
+				t.Errorf("append %d failed: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	results, err := store.Load()
+	if err != nil || len(results) != 10 {
+		t.Fatalf("expected all 10 results to be stored, got %d (%v)", len(results), err)
+	}
+	leftovers, _ := filepath.Glob(lockPath + "*")
+	if len(leftovers) != 0 {
+		t.Fatalf("expected no lock files to be left behind, got %v", leftovers)
+	}
+}
+
+func TestUnlockKeepsAnotherOwnersLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), historyFileName)
+	release, err := lock(path)
+	if err != nil {
+		t.Fatalf("expected lock, got %v", err)
+	}
+	// The lock was broken and taken over by another writer.
+	if err := os.WriteFile(path+".lock", []byte("other"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if _, err := os.Stat(path + ".lock"); err != nil {
+		t.Fatalf("expected the other writer's lock to be kept, got %v", err)
+	}
+}
+
+func TestStoreConcurrentImportsAddResultsOnce(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), historyFileName))
+	imported := []models.Result{
+		{Source: SourceMonkeytype, ExternalID: "a1", WPM: 80},
+		{Source: SourceMonkeytype, ExternalID: "a2", WPM: 90},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := store.Import(imported); err != nil {
+				t.Errorf("import failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	results, err := store.Load()
+	if err != nil || len(results) != 2 {
+		t.Fatalf("expected each result to be imported once, got %d (%v)", len(results), err)
+	}
+	if fresh, duplicates, err := store.Import(imported); err != nil || len(fresh) != 0 || duplicates != 2 {
+		t.Fatalf("expected a repeated import to add nothing, got %d new and %d duplicates (%v)", len(fresh), duplicates, err)
+	}
+}
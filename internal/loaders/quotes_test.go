@@ -24,3 +24,19 @@ func TestLoadQuotesInvalidLanguage(t *testing.T) {
 		t.Fatalf("expected error for missing language")
 	}
 }
+
+func TestLoadQuotesDecodesLengthGroups(t *testing.T) {
+	data, err := LoadQuotes(models.English)
+	if err != nil {
+		t.Fatalf("expected quotes to load, got %v", err)
+	}
+	if len(data.Groups) != len(models.QuoteLengths()) {
+		t.Fatalf("expected a group per quote length, got %v", data.Groups)
+	}
+	if data.Quotes[0].Length == 0 {
+		t.Fatalf("expected quote lengths to be decoded")
+	}
+	if short := data.WithLengths([]models.QuoteLength{models.ShortQuotes}); len(short.Quotes) == 0 || len(short.Quotes) == len(data.Quotes) {
+		t.Fatalf("expected short quotes to be a strict subset, got %d of %d", len(short.Quotes), len(data.Quotes))
+	}
+}
@@ -5,9 +5,11 @@ import "strings"
 type Mode string
 
 const (
-	QuoteMode Mode = "quote"
-	WordsMode Mode = "words"
-	TimeMode  Mode = "time"
+	QuoteMode  Mode = "quote"
+	WordsMode  Mode = "words"
+	TimeMode   Mode = "time"
+	CustomMode Mode = "custom"
+	ZenMode    Mode = "zen"
 )
 
 type Language string
@@ -39,6 +41,106 @@ type Duration int
 
 type WordCount int
 
+// WPMMetric selects how typing speed is scored.
+type WPMMetric string
+
+const (
+	// CharacterWPM counts correctly typed characters divided by five.
+	CharacterWPM WPMMetric = "chars"
+	// WordWPM counts whitespace separated words, as earlier releases did.
+	WordWPM WPMMetric = "words"
+)
+
+// Difficulty sets how strictly mistakes are punished.
+type Difficulty string
+
+const (
+	// NormalDifficulty lets mistakes through; they only cost accuracy.
+	NormalDifficulty Difficulty = "normal"
+	// ExpertDifficulty fails the test when a word is submitted with
+	// mistakes in it.
+	ExpertDifficulty Difficulty = "expert"
+	// MasterDifficulty fails the test on the first wrong keystroke.
+	MasterDifficulty Difficulty = "master"
+)
+
+// StopOnError selects whether input is blocked until a mistake is fixed.
+type StopOnError string
+
+const (
+	// StopOnErrorOff accepts every key.
+	StopOnErrorOff StopOnError = "off"
+	// StopOnLetter rejects keys that would type a wrong character, so the
+	// right one has to be typed before moving on.
+	StopOnLetter StopOnError = "letter"
+	// StopOnWord accepts wrong characters but blocks finishing a word until
+	// its mistakes are corrected.
+	StopOnWord StopOnError = "word"
+)
+
+// Confidence limits how mistakes can be corrected with backspace.
+type Confidence string
+
+const (
+	// ConfidenceOff allows backspace everywhere.
+	ConfidenceOff Confidence = "off"
+	// ConfidenceOn allows backspace within the current word only.
+	ConfidenceOn Confidence = "on"
+	// ConfidenceMax disables backspace entirely.
+	ConfidenceMax Confidence = "max"
+)
+
+// CustomOptions describes where custom mode reads its text and how the text
+// is split into passages.
+type CustomOptions struct {
+	// File is the path to read, or "" or "-" for standard input.
+	File string
+	// PassageWords, when set, picks a random run of that many words for
+	// each test.
+	PassageWords int
+	// ChunkWords, when set, splits the text into consecutive chunks of that
+	// many words that are typed in order.
+	ChunkWords int
+	// CollapseWhitespace joins lines and squeezes runs of whitespace into
+	// single spaces.
+	CollapseWhitespace bool
+}
+
+// SnippetUnit selects how source files are cut into passages.
+type SnippetUnit string
+
+const (
+	// FunctionSnippets types whole functions, falling back to line windows
+	// for files without any.
+	FunctionSnippets SnippetUnit = "functions"
+	// LineSnippets types fixed-size windows of consecutive lines.
+	LineSnippets SnippetUnit = "lines"
+)
+
+// SourceOptions describes the source files quote mode types from instead of
+// the built-in quotes.
+type SourceOptions struct {
+	// Paths lists files, directories and glob patterns to read.
+	Paths []string
+	// GoModule is the root of a Go module whose top-level declarations are
+	// typed, read with go/parser.
+	GoModule string
+	// GoPackages limits GoModule to these import paths; a trailing "/..."
+	// matches everything below a path.
+	GoPackages []string
+	// Snippet selects whether functions or line windows are typed.
+	Snippet SnippetUnit
+	// Lines is the size of a line window and the longest function kept.
+	Lines int
+	// MinLines is the shortest function kept.
+	MinLines int
+}
+
+// Enabled reports whether any source files were given.
+func (o SourceOptions) Enabled() bool {
+	return len(o.Paths) > 0 || o.GoModule != ""
+}
+
 type Config struct {
 	Mode               Mode
 	Language           Language
@@ -46,6 +148,17 @@ type Config struct {
 	WordCount          WordCount
 	IncludePunctuation bool
 	IncludeNumbers     bool
+	WPMMetric          WPMMetric
+	Difficulty         Difficulty
+	StopOnError        StopOnError
+	Confidence         Confidence
+	Custom             CustomOptions
+	Source             SourceOptions
+	// QuoteLengths limits quote mode to these length groups; empty means
+	// every quote.
+	QuoteLengths []QuoteLength
+	// QuoteID, when set, types this quote every time.
+	QuoteID int
 }
 
 var supportedLanguages = []Language{
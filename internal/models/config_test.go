@@ -30,3 +30,15 @@ func TestSupportedLanguagesReturnsCopy(t *testing.T) {
 		t.Fatalf("expected SupportedLanguages to return copy; got %q instead of %q", fresh[0], originalFirst)
 	}
 }
+
+func TestLanguageScoring(t *testing.T) {
+	if scoring := English.Scoring(); scoring.CharsPerWord != 5 || scoring.Unit != "WPM" || !scoring.Segmented {
+		t.Fatalf("unexpected english scoring: %+v", scoring)
+	}
+	if scoring := Chinese.Scoring(); scoring.CharsPerWord != 1 || scoring.Unit != "CPM" || scoring.Segmented {
+		t.Fatalf("unexpected chinese scoring: %+v", scoring)
+	}
+	if scoring := Language("unknown").Scoring(); scoring.CharsPerWord != 5 {
+		t.Fatalf("expected unknown languages to use the default scoring, got %+v", scoring)
+	}
+}
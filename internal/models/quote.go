@@ -2,9 +2,96 @@ package models
 
 type Quote struct {
 	Text string `json:"text"`
+	ID   int    `json:"id"`
+	// Source credits where the quote comes from, e.g. a book or film.
+	Source string `json:"source"`
+	// Length is the quote's length in characters as recorded in the data.
+	Length int `json:"length"`
 }
 
 type LanguageQuotes struct {
 	Language Language `json:"language"`
-	Quotes   []Quote  `json:"quotes"`
+	// Groups holds the inclusive [min, max] length range of each
+	// QuoteLength, in the order of QuoteLengths.
+	Groups [][2]int `json:"groups"`
+	Quotes []Quote  `json:"quotes"`
+}
+
+// ByID returns the quote with the given id.
+func (lq LanguageQuotes) ByID(id int) (Quote, bool) {
+	for _, quote := range lq.Quotes {
+		if quote.ID == id {
+			return quote, true
+		}
+	}
+	return Quote{}, false
+}
+
+// QuoteLength names one of the length groups in the quote data.
+type QuoteLength string
+
+const (
+	ShortQuotes  QuoteLength = "short"
+	MediumQuotes QuoteLength = "medium"
+	LongQuotes   QuoteLength = "long"
+	ThiccQuotes  QuoteLength = "thicc"
+)
+
+var quoteLengths = []QuoteLength{ShortQuotes, MediumQuotes, LongQuotes, ThiccQuotes}
+
+// QuoteLengths returns the length groups from shortest to longest.
+func QuoteLengths() []QuoteLength {
+	result := make([]QuoteLength, len(quoteLengths))
+	copy(result, quoteLengths)
+	return result
+}
+
+// group returns the index of l in the quote data's groups.
+func (l QuoteLength) group() int {
+	for i, length := range quoteLengths {
+		if length == l {
+			return i
+		}
+	}
+	return -1
+}
+
+// WithLengths returns the quotes that fall into any of the given length
+// groups. No lengths keeps every quote.
+func (lq LanguageQuotes) WithLengths(lengths []QuoteLength) LanguageQuotes {
+	if len(lengths) == 0 {
+		return lq
+	}
+
+	filtered := lq
+	filtered.Quotes = nil
+	for _, quote := range lq.Quotes {
+		for _, length := range lengths {
+			if lq.InGroup(quote, length) {
+				filtered.Quotes = append(filtered.Quotes, quote)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// InGroup reports whether quote falls into the length group.
+func (lq LanguageQuotes) InGroup(quote Quote, length QuoteLength) bool {
+	index := length.group()
+	if index < 0 || index >= len(lq.Groups) {
+		return false
+	}
+	bounds := lq.Groups[index]
+	return quote.Length >= bounds[0] && quote.Length <= bounds[1]
+}
+
+// LengthOf returns the length group quote falls into.
+func (lq LanguageQuotes) LengthOf(quote Quote) (QuoteLength, bool) {
+	for _, length := range quoteLengths {
+		if lq.InGroup(quote, length) {
+			return length, true
+		}
+	}
+	return "", false
 }
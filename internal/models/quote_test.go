@@ -0,0 +1,43 @@
+package models
+
+import "testing"
+
+func TestWithLengthsUsesGroups(t *testing.T) {
+	quotes := LanguageQuotes{
+		Groups: [][2]int{{0, 10}, {11, 20}, {21, 30}, {31, 9999}},
+		Quotes: []Quote{
+			{ID: 1, Length: 5},
+			{ID: 2, Length: 15},
+			{ID: 3, Length: 25},
+			{ID: 4, Length: 500},
+		},
+	}
+
+	if got := quotes.WithLengths(nil); len(got.Quotes) != 4 {
+		t.Fatalf("expected no lengths to keep every quote, got %d", len(got.Quotes))
+	}
+
+	got := quotes.WithLengths([]QuoteLength{ShortQuotes, ThiccQuotes})
+	if len(got.Quotes) != 2 || got.Quotes[0].ID != 1 || got.Quotes[1].ID != 4 {
+		t.Fatalf("expected short and thicc quotes, got %+v", got.Quotes)
+	}
+	if len(quotes.Quotes) != 4 {
+		t.Fatalf("expected the original quotes to be left alone")
+	}
+}
+
+func TestInGroupWithoutGroups(t *testing.T) {
+	if (LanguageQuotes{}).InGroup(Quote{Length: 5}, ShortQuotes) {
+		t.Fatalf("expected no match when the data has no groups")
+	}
+}
+
+func TestByID(t *testing.T) {
+	quotes := LanguageQuotes{Quotes: []Quote{{ID: 3, Text: "three", Source: "Numbers"}, {ID: 9, Text: "nine"}}}
+	if quote, ok := quotes.ByID(3); !ok || quote.Source != "Numbers" {
+		t.Fatalf("expected quote 3 with its source, got %+v", quote)
+	}
+	if _, ok := quotes.ByID(4); ok {
+		t.Fatalf("expected no quote with id 4")
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Outcome describes how a test ended.
+type Outcome string
+
+const (
+	// OutcomeCompleted tests reached the end of their text or time limit.
+	OutcomeCompleted Outcome = "completed"
+	// OutcomeIncomplete tests were finished early with Tab.
+	OutcomeIncomplete Outcome = "incomplete"
+	// OutcomeFailed tests were stopped by a mistake under the expert or
+	// master difficulty.
+	OutcomeFailed Outcome = "failed"
+)
+
+// Result is a finished typing test as stored in the results history.
+type Result struct {
+	ID             string    `json:"id"`
+	Mode           Mode      `json:"mode"`
+	Language       Language  `json:"language"`
+	Duration       Duration  `json:"duration"`
+	WordCount      WordCount `json:"word_count"`
+	Punctuation    bool      `json:"punctuation"`
+	Numbers        bool      `json:"numbers"`
+	QuoteID        int       `json:"quote_id"`
+	Outcome        Outcome   `json:"outcome"`
+	Metric         WPMMetric `json:"wpm_metric"`
+	Unit           string    `json:"unit"`
+	WPM            float64   `json:"wpm"`
+	RawWPM         float64   `json:"raw_wpm"`
+	NetWPM         float64   `json:"net_wpm"`
+	CPM            float64   `json:"cpm"`
+	Accuracy       float64   `json:"accuracy"`
+	Consistency    float64   `json:"consistency"`
+	CorrectChars   int       `json:"correct_chars"`
+	IncorrectChars int       `json:"incorrect_chars"`
+	ExtraChars     int       `json:"extra_chars"`
+	MissedChars    int       `json:"missed_chars"`
+	StartedAt      time.Time `json:"started_at"`
+	FinishedAt     time.Time `json:"finished_at"`
+	ElapsedSeconds float64   `json:"elapsed_seconds"`
+	// Source names where an imported result came from, e.g. "monkeytype",
+	// and ExternalID is its id there. Both are empty for local tests.
+	Source     string `json:"source"`
+	ExternalID string `json:"external_id"`
+	// Difficulty is left empty for tests run at the normal difficulty.
+	Difficulty Difficulty `json:"difficulty"`
+}
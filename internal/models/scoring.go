@@ -0,0 +1,27 @@
+package models
+
+// Scoring describes how typing speed is measured for a language.
+type Scoring struct {
+	// CharsPerWord is the number of characters counted as one word.
+	CharsPerWord float64
+	// Unit labels the resulting speed, e.g. "WPM" or "CPM".
+	Unit string
+	// Segmented reports whether words are separated by whitespace.
+	Segmented bool
+}
+
+var defaultScoring = Scoring{CharsPerWord: 5, Unit: "WPM", Segmented: true}
+
+// Hanzi carry roughly a word's worth of meaning each and are not separated by
+// spaces, so Chinese speed is reported as characters per minute.
+var languageScoring = map[Language]Scoring{
+	Chinese: {CharsPerWord: 1, Unit: "CPM", Segmented: false},
+}
+
+// Scoring returns the speed scoring strategy for the language.
+func (l Language) Scoring() Scoring {
+	if scoring, ok := languageScoring[l]; ok {
+		return scoring
+	}
+	return defaultScoring
+}
@@ -0,0 +1,80 @@
+package custom
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-isatty"
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+	"github.com/neilsmahajan/typing-test-tui/internal/modes"
+	"github.com/neilsmahajan/typing-test-tui/internal/ui/quote_input"
+	"github.com/neilsmahajan/typing-test-tui/internal/ui/typing"
+)
+
+const stdinName = "stdin"
+
+func Run(cfg models.Config) error {
+	text, name, fromStdin, err := readText(cfg.Custom.File, os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	source, err := NewSource(text, name, cfg.Custom)
+	if err != nil {
+		return fmt.Errorf("error preparing custom text: %w", err)
+	}
+
+	sessionOptions := modes.SessionOptions(cfg)
+
+	indicator := ""
+	if !cfg.Custom.CollapseWhitespace && strings.Contains(text, "\n") {
+		indicator = typing.DefaultNewlineIndicator
+	}
+
+	model := quote_input.NewModel(quote_input.Options{
+		Header:           "Custom Mode",
+		Prompt:           "Press Enter for the next passage or Ctrl+C to exit.",
+		Language:         cfg.Language,
+		NewlineIndicator: indicator,
+		Source:           source,
+	}, sessionOptions)
+
+	var options []tea.ProgramOption
+	if fromStdin {
+		// Standard input carried the text, so read keys from the terminal.
+		options = append(options, tea.WithInputTTY())
+	}
+	p := tea.NewProgram(model, options...)
+
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("error running program: %w", err)
+	}
+
+	return nil
+}
+
+// readText loads the custom text from path, or from stdin when path is empty
+// or "-". It returns the text with a name for display, and reports whether
+// stdin carried it so keys are then read from the terminal instead.
+func readText(path string, stdin *os.File) (text, name string, fromStdin bool, err error) {
+	if path != "" && path != "-" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", "", false, fmt.Errorf("error reading custom text: %w", err)
+		}
+		return string(data), filepath.Base(path), false, nil
+	}
+
+	if isatty.IsTerminal(stdin.Fd()) || isatty.IsCygwinTerminal(stdin.Fd()) {
+		return "", "", false, fmt.Errorf("custom mode needs text: pass --file or pipe text on standard input")
+	}
+	data, err := io.ReadAll(stdin)
+	if err != nil {
+		return "", "", false, fmt.Errorf("error reading standard input: %w", err)
+	}
+	return string(data), stdinName, true, nil
+}
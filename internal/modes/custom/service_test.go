@@ -0,0 +1,42 @@
+package custom
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadTextFromFileNamedStdin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), stdinName)
+	if err := os.WriteFile(path, []byte("from a file"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	text, name, fromStdin, err := readText(path, os.Stdin)
+	if err != nil {
+		t.Fatalf("expected file to be read, got %v", err)
+	}
+	if text != "from a file" || name != stdinName || fromStdin {
+		t.Fatalf("expected a file named %q not to count as stdin, got %q, %q, %v", stdinName, text, name, fromStdin)
+	}
+}
+
+func TestReadTextFromStdin(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	if _, err := writer.WriteString("piped text"); err != nil {
+		t.Fatal(err)
+	}
+	writer.Close()
+
+	text, _, fromStdin, err := readText("-", reader)
+	if err != nil {
+		t.Fatalf("expected stdin to be read, got %v", err)
+	}
+	if text != "piped text" || !fromStdin {
+		t.Fatalf("expected piped text from stdin, got %q, %v", text, fromStdin)
+	}
+}
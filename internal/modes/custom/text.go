@@ -0,0 +1,132 @@
+package custom
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+	"github.com/neilsmahajan/typing-test-tui/internal/ui/quote_input"
+)
+
+var wordPattern = regexp.MustCompile(`\S+`)
+
+// normalizeText unifies line endings and strips trailing spaces from every
+// line, which cannot be seen on screen. With collapse set the whole text is
+// joined into a single line of space-separated words.
+func normalizeText(text string, collapse bool) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	if collapse {
+		return strings.Join(strings.Fields(text), " ")
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// wordSpans returns the byte range of every word in text.
+func wordSpans(text string) [][]int {
+	return wordPattern.FindAllStringIndex(text, -1)
+}
+
+// excerpt returns count words of text starting at word index from, keeping
+// the original spacing and line breaks between them.
+func excerpt(text string, spans [][]int, from, count int) string {
+	end := from + count
+	if end > len(spans) {
+		end = len(spans)
+	}
+	return text[spans[from][0]:spans[end-1][1]]
+}
+
+// chunks splits text into consecutive runs of size words.
+func chunks(text string, size int) []string {
+	spans := wordSpans(text)
+	var result []string
+	for from := 0; from < len(spans); from += size {
+		result = append(result, excerpt(text, spans, from, size))
+	}
+	return result
+}
+
+// NewSource prepares text according to opts and returns the passages custom
+// mode types. name labels the text in the subtitle, e.g. its file name.
+func NewSource(text, name string, opts models.CustomOptions) (quote_input.Source, error) {
+	if opts.PassageWords < 0 || opts.ChunkWords < 0 {
+		return nil, fmt.Errorf("passage and chunk sizes must not be negative")
+	}
+	if opts.PassageWords > 0 && opts.ChunkWords > 0 {
+		return nil, fmt.Errorf("passage-words and chunk-words cannot be combined")
+	}
+
+	text = normalizeText(text, opts.CollapseWhitespace)
+	spans := wordSpans(text)
+	if len(spans) == 0 {
+		return nil, fmt.Errorf("%s does not contain any text to type", name)
+	}
+
+	switch {
+	case opts.ChunkWords > 0 && len(spans) > opts.ChunkWords:
+		return &chunkSource{name: name, chunks: chunks(text, opts.ChunkWords)}, nil
+	case opts.PassageWords > 0 && len(spans) > opts.PassageWords:
+		return &passageSource{
+			name:  name,
+			text:  text,
+			spans: spans,
+			size:  opts.PassageWords,
+			rng:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		}, nil
+	default:
+		return wholeSource{name: name, text: text}, nil
+	}
+}
+
+// wholeSource repeats the full text.
+type wholeSource struct {
+	name string
+	text string
+}
+
+func (s wholeSource) Next() quote_input.Passage {
+	return quote_input.Passage{Text: s.text, Label: s.name}
+}
+
+// chunkSource walks through the chunks in order, starting over after the
+// last one.
+type chunkSource struct {
+	name   string
+	chunks []string
+	next   int
+}
+
+func (s *chunkSource) Next() quote_input.Passage {
+	index := s.next % len(s.chunks)
+	s.next = index + 1
+	return quote_input.Passage{
+		Text:  s.chunks[index],
+		Label: fmt.Sprintf("%s · chunk %d/%d", s.name, index+1, len(s.chunks)),
+	}
+}
+
+// passageSource picks a random run of words for every test.
+type passageSource struct {
+	name  string
+	text  string
+	spans [][]int
+	size  int
+	rng   *rand.Rand
+}
+
+func (s *passageSource) Next() quote_input.Passage {
+	from := s.rng.Intn(len(s.spans) - s.size + 1)
+	return quote_input.Passage{
+		Text:  excerpt(s.text, s.spans, from, s.size),
+		Label: fmt.Sprintf("%s · words %d-%d", s.name, from+1, from+s.size),
+	}
+}
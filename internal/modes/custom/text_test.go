@@ -0,0 +1,76 @@
+package custom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+func TestNormalizeText(t *testing.T) {
+	input := "\r\nfirst line  \r\n\tindented\t\nlast   words \n\n"
+	if got := normalizeText(input, false); got != "first line\n\tindented\nlast   words" {
+		t.Fatalf("unexpected normalized text %q", got)
+	}
+	if got := normalizeText(input, true); got != "first line indented last words" {
+		t.Fatalf("unexpected collapsed text %q", got)
+	}
+}
+
+func TestChunkSourceCyclesInOrder(t *testing.T) {
+	source, err := NewSource("one two three\nfour five", "notes.txt", models.CustomOptions{ChunkWords: 2})
+	if err != nil {
+		t.Fatalf("expected source, got %v", err)
+	}
+
+	want := []string{"one two", "three\nfour", "five", "one two"}
+	for i, text := range want {
+		passage := source.Next()
+		if passage.Text != text {
+			t.Fatalf("chunk %d: expected %q, got %q", i, text, passage.Text)
+		}
+	}
+	if label := source.Next().Label; label != "notes.txt · chunk 2/3" {
+		t.Fatalf("unexpected label %q", label)
+	}
+}
+
+func TestPassageSourcePicksConsecutiveWords(t *testing.T) {
+	text := "a b c d e f g h"
+	source, err := NewSource(text, "stdin", models.CustomOptions{PassageWords: 3})
+	if err != nil {
+		t.Fatalf("expected source, got %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		passage := source.Next()
+		if len(strings.Fields(passage.Text)) != 3 || !strings.Contains(text, passage.Text) {
+			t.Fatalf("expected three consecutive words, got %q", passage.Text)
+		}
+	}
+}
+
+func TestNewSourceUsesWholeTextWhenShort(t *testing.T) {
+	source, err := NewSource("just a few words", "notes.txt", models.CustomOptions{ChunkWords: 50})
+	if err != nil {
+		t.Fatalf("expected source, got %v", err)
+	}
+	if passage := source.Next(); passage.Text != "just a few words" || passage.Label != "notes.txt" {
+		t.Fatalf("unexpected passage %+v", passage)
+	}
+}
+
+func TestNewSourceErrors(t *testing.T) {
+	if _, err := NewSource(" \n\t ", "empty.txt", models.CustomOptions{}); err == nil {
+		t.Fatalf("expected error for empty text")
+	}
+	if _, err := NewSource("text", "notes.txt", models.CustomOptions{PassageWords: 2, ChunkWords: 2}); err == nil {
+		t.Fatalf("expected error when combining passage and chunk sizes")
+	}
+}
+
+func TestRunMissingFile(t *testing.T) {
+	cfg := models.Config{Mode: models.CustomMode, Language: models.English, Custom: models.CustomOptions{File: "does-not-exist.txt"}}
+	if err := Run(cfg); err == nil {
+		t.Fatalf("expected error for a missing file")
+	}
+}
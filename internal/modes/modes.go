@@ -0,0 +1,24 @@
+// Package modes holds what the mode services under it share.
+package modes
+
+import (
+	"github.com/neilsmahajan/typing-test-tui/internal/history"
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+	"github.com/neilsmahajan/typing-test-tui/internal/ui/typing"
+)
+
+// SessionOptions derives the session options for cfg and, when the results
+// history can be opened, records finished tests there and starts from the
+// personal best stored for cfg.
+func SessionOptions(cfg models.Config) typing.SessionOptions {
+	sessionOptions := typing.SessionOptionsFromConfig(cfg)
+	if store, err := history.Default(); err == nil {
+		sessionOptions.Recorder = store
+		if results, err := store.Load(); err == nil {
+			if best, ok := history.PersonalBest(results, history.ConfigKey(cfg)); ok {
+				sessionOptions.PersonalBest = best.WPM
+			}
+		}
+	}
+	return sessionOptions
+}
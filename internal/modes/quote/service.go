@@ -2,20 +2,76 @@ package quote
 
 import (
 	"fmt"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/neilsmahajan/typing-test-tui/internal/loaders"
 	"github.com/neilsmahajan/typing-test-tui/internal/models"
+	"github.com/neilsmahajan/typing-test-tui/internal/modes"
+	"github.com/neilsmahajan/typing-test-tui/internal/snippets"
 	"github.com/neilsmahajan/typing-test-tui/internal/ui/quote_input"
+	"github.com/neilsmahajan/typing-test-tui/internal/ui/typing"
 )
 
 func Run(cfg models.Config) error {
+	if cfg.Source.Enabled() {
+		return runSnippets(cfg)
+	}
+
 	languageQuotes, err := loaders.LoadQuotes(cfg.Language)
 	if err != nil {
 		return fmt.Errorf("error loading quotes: %w", err)
 	}
+	if cfg.QuoteID > 0 {
+		quote, ok := languageQuotes.ByID(cfg.QuoteID)
+		if !ok {
+			return fmt.Errorf("no quote with id %d for %s", cfg.QuoteID, cfg.Language)
+		}
+		languageQuotes.Quotes = []models.Quote{quote}
+	}
+	languageQuotes = languageQuotes.WithLengths(cfg.QuoteLengths)
+	if len(languageQuotes.Quotes) == 0 {
+		return fmt.Errorf("no %s quotes for %s", joinLengths(cfg.QuoteLengths), cfg.Language)
+	}
+
+	return run(quote_input.InitialModel(languageQuotes, modes.SessionOptions(cfg)))
+}
+
+// runSnippets types functions or line windows cut from the source files in
+// cfg.Source, or declarations parsed from a Go module, instead of the
+// built-in quotes.
+func runSnippets(cfg models.Config) error {
+	var list []snippets.Snippet
+	var err error
+	if cfg.Source.GoModule != "" {
+		list, err = snippets.LoadGo(cfg.Source)
+	} else {
+		list, err = snippets.Load(cfg.Source.Paths, cfg.Source)
+	}
+	if err != nil {
+		return fmt.Errorf("error loading source files: %w", err)
+	}
+	cfg.Language = snippetLanguage(cfg.Language, list)
+
+	return run(quote_input.NewModel(quote_input.Options{
+		Header:           "Quote Mode · Source Files",
+		Prompt:           "Press Enter for another snippet or Ctrl+C to exit.",
+		Language:         cfg.Language,
+		NewlineIndicator: typing.DefaultNewlineIndicator,
+		Source:           newSnippetSource(list),
+	}, modes.SessionOptions(cfg)))
+}
+
+func joinLengths(lengths []models.QuoteLength) string {
+	names := make([]string, len(lengths))
+	for i, length := range lengths {
+		names[i] = string(length)
+	}
+	return strings.Join(names, " or ")
+}
 
-	p := tea.NewProgram(quote_input.InitialModel(languageQuotes))
+func run(model tea.Model) error {
+	p := tea.NewProgram(model)
 
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("error running program: %w", err)
@@ -1,9 +1,11 @@
 package quote
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/neilsmahajan/typing-test-tui/internal/models"
+	"github.com/neilsmahajan/typing-test-tui/internal/snippets"
 )
 
 func TestRunInvalidLanguage(t *testing.T) {
@@ -12,3 +14,35 @@ func TestRunInvalidLanguage(t *testing.T) {
 		t.Fatalf("expected error when language data is missing")
 	}
 }
+
+func TestSnippetLanguage(t *testing.T) {
+	goSnippets := []snippets.Snippet{{Path: "a.go"}, {Path: "pkg/b.go"}}
+	if got := snippetLanguage(models.English, goSnippets); got != models.Go {
+		t.Fatalf("expected language inferred from the files, got %q", got)
+	}
+	if got := snippetLanguage(models.Rust, goSnippets); got != models.Rust {
+		t.Fatalf("expected an explicit code language to win, got %q", got)
+	}
+	mixed := append(goSnippets, snippets.Snippet{Path: "c.py"})
+	if got := snippetLanguage(models.English, mixed); got != models.English {
+		t.Fatalf("expected mixed languages to keep the configured language, got %q", got)
+	}
+}
+
+func TestRunMissingSourceFile(t *testing.T) {
+	cfg := models.Config{
+		Mode:     models.QuoteMode,
+		Language: models.English,
+		Source:   models.SourceOptions{Paths: []string{"does-not-exist.go"}, Snippet: models.FunctionSnippets, Lines: 15},
+	}
+	if err := Run(cfg); err == nil {
+		t.Fatalf("expected error when the source file is missing")
+	}
+}
+
+func TestRunUnknownQuoteID(t *testing.T) {
+	cfg := models.Config{Mode: models.QuoteMode, Language: models.English, QuoteID: 1 << 30}
+	if err := Run(cfg); err == nil || !strings.Contains(err.Error(), "no quote with id") {
+		t.Fatalf("expected error for a missing quote id, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package quote
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+	"github.com/neilsmahajan/typing-test-tui/internal/snippets"
+	"github.com/neilsmahajan/typing-test-tui/internal/ui/quote_input"
+)
+
+// snippetSource picks a random snippet of the user's own code for every
+// test.
+type snippetSource struct {
+	snippets []snippets.Snippet
+	rng      *rand.Rand
+}
+
+func newSnippetSource(list []snippets.Snippet) *snippetSource {
+	return &snippetSource{
+		snippets: list,
+		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (s *snippetSource) Next() quote_input.Passage {
+	snippet := s.snippets[s.rng.Intn(len(s.snippets))]
+	return quote_input.Passage{Text: snippet.Text, Label: snippet.Label()}
+}
+
+// snippetLanguage picks the language results are recorded under. An explicit
+// code language wins; otherwise the files' language is used when they all
+// share one.
+func snippetLanguage(configured models.Language, list []snippets.Snippet) models.Language {
+	if strings.HasPrefix(string(configured), "code_") {
+		return configured
+	}
+
+	var found models.Language
+	for _, snippet := range list {
+		language, ok := snippet.Language()
+		if !ok || (found != "" && language != found) {
+			return configured
+		}
+		found = language
+	}
+	if found == "" {
+		return configured
+	}
+	return found
+}
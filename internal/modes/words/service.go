@@ -6,6 +6,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/neilsmahajan/typing-test-tui/internal/loaders"
 	"github.com/neilsmahajan/typing-test-tui/internal/models"
+	"github.com/neilsmahajan/typing-test-tui/internal/modes"
 	"github.com/neilsmahajan/typing-test-tui/internal/ui/words_input"
 )
 
@@ -15,7 +16,9 @@ func Run(cfg models.Config) error {
 		return fmt.Errorf("error loading words: %w", err)
 	}
 
-	p := tea.NewProgram(words_input.InitialModel(languageWords, cfg.WordCount, cfg.IncludePunctuation, cfg.IncludeNumbers))
+	sessionOptions := modes.SessionOptions(cfg)
+
+	p := tea.NewProgram(words_input.InitialModel(languageWords, cfg.WordCount, cfg.IncludePunctuation, cfg.IncludeNumbers, sessionOptions))
 
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("error running program: %w", err)
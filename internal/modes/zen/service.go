@@ -0,0 +1,22 @@
+package zen
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+	"github.com/neilsmahajan/typing-test-tui/internal/modes"
+	"github.com/neilsmahajan/typing-test-tui/internal/ui/zen_input"
+)
+
+func Run(cfg models.Config) error {
+	sessionOptions := modes.SessionOptions(cfg)
+
+	p := tea.NewProgram(zen_input.InitialModel(cfg.Language, sessionOptions))
+
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("error running program: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+// Package quotesearch finds quotes by their text or source, tolerating small
+// typos in the search terms.
+package quotesearch
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+// Match is a quote found by a search, best matches first.
+type Match struct {
+	Quote  models.Quote
+	Length models.QuoteLength
+	rank   rank
+	// distance sums the edits needed to match fuzzy terms.
+	distance int
+}
+
+// rank orders the ways a quote can match, best first.
+type rank int
+
+const (
+	phraseInText rank = iota
+	phraseInSource
+	allTerms
+	fuzzyTerms
+	noMatch
+)
+
+type entry struct {
+	quote  models.Quote
+	length models.QuoteLength
+	text   string
+	source string
+	words  []string
+}
+
+// Index holds the quotes of one language prepared for searching.
+type Index struct {
+	entries []entry
+}
+
+// NewIndex prepares the quotes in languageQuotes for searching.
+func NewIndex(languageQuotes models.LanguageQuotes) *Index {
+	entries := make([]entry, len(languageQuotes.Quotes))
+	for i, quote := range languageQuotes.Quotes {
+		length, _ := languageQuotes.LengthOf(quote)
+		text := normalize(quote.Text)
+		source := normalize(quote.Source)
+		entries[i] = entry{
+			quote:  quote,
+			length: length,
+			text:   text,
+			source: source,
+			words:  words(text + " " + source),
+		}
+	}
+	return &Index{entries: entries}
+}
+
+// Len returns the number of quotes in the index.
+func (ix *Index) Len() int {
+	return len(ix.entries)
+}
+
+// Search returns the quotes matching query. A quote matches when the query
+// appears in its text or source, when every term of the query does, or,
+// unless exact is set, when every term is within a typo or two of a word in
+// the quote. An empty query matches every quote in id order. A positive limit
+// caps the number of matches.
+func (ix *Index) Search(query string, exact bool, limit int) []Match {
+	query = normalize(query)
+	terms := words(query)
+
+	var matches []Match
+	for _, e := range ix.entries {
+		r, distance := phraseInText, 0
+		if len(terms) > 0 {
+			r, distance = e.match(query, terms, exact)
+		}
+		if r == noMatch {
+			continue
+		}
+		matches = append(matches, Match{Quote: e.quote, Length: e.length, rank: r, distance: distance})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		if a.distance != b.distance {
+			return a.distance < b.distance
+		}
+		return a.Quote.ID < b.Quote.ID
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+func (e entry) match(query string, terms []string, exact bool) (rank, int) {
+	switch {
+	case strings.Contains(e.text, query):
+		return phraseInText, 0
+	case strings.Contains(e.source, query):
+		return phraseInSource, 0
+	}
+
+	all := true
+	for _, term := range terms {
+		if !strings.Contains(e.text, term) && !strings.Contains(e.source, term) {
+			all = false
+			break
+		}
+	}
+	if all {
+		return allTerms, 0
+	}
+	if exact {
+		return noMatch, 0
+	}
+
+	total := 0
+	for _, term := range terms {
+		distance, ok := closestWord(term, e.words)
+		if !ok {
+			return noMatch, 0
+		}
+		total += distance
+	}
+	return fuzzyTerms, total
+}
+
+// closestWord returns the smallest edit distance between term and a word,
+// counting words that start with term as exact.
+func closestWord(term string, words []string) (int, bool) {
+	allowed := allowedTypos(term)
+	best := allowed + 1
+	for _, word := range words {
+		if strings.HasPrefix(word, term) {
+			return 0, true
+		}
+		if d := distance(term, word, best); d < best {
+			best = d
+		}
+	}
+	return best, best <= allowed
+}
+
+// allowedTypos scales the edits a term may be off by with its length, so
+// short words must match exactly.
+func allowedTypos(term string) int {
+	switch n := len([]rune(term)); {
+	case n <= 3:
+		return 0
+	case n <= 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// distance is the Levenshtein distance between a and b, or a value of at
+// least limit once it is known to reach limit.
+func distance(a, b string, limit int) int {
+	ar, br := []rune(a), []rune(b)
+	if diff := len(ar) - len(br); diff >= limit || -diff >= limit {
+		return limit
+	}
+
+	previous := make([]int, len(br)+1)
+	current := make([]int, len(br)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		rowMin := current[0]
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			rowMin = min(rowMin, current[j])
+		}
+		if rowMin >= limit {
+			return limit
+		}
+		previous, current = current, previous
+	}
+	return previous[len(br)]
+}
+
+// normalize lowercases text and squeezes whitespace so phrases match across
+// line breaks.
+func normalize(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+func words(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+	})
+}
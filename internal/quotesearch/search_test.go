@@ -0,0 +1,94 @@
+package quotesearch
+
+import (
+	"testing"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/loaders"
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+func fixture() *Index {
+	return NewIndex(models.LanguageQuotes{
+		Language: models.English,
+		Groups:   [][2]int{{0, 40}, {41, 80}, {81, 120}, {121, 9999}},
+		Quotes: []models.Quote{
+			{ID: 1, Text: "The only way out is through.", Source: "Robert Frost", Length: 28},
+			{ID: 2, Text: "Frost covered the windows\nevery morning that winter.", Source: "A Cold Year", Length: 52},
+			{ID: 3, Text: "Imagination is more important than knowledge.", Source: "Albert Einstein", Length: 45},
+			{ID: 4, Text: "Knowledge speaks, but wisdom listens.", Source: "Jimi Hendrix", Length: 37},
+		},
+	})
+}
+
+func ids(matches []Match) []int {
+	result := make([]int, len(matches))
+	for i, match := range matches {
+		result[i] = match.Quote.ID
+	}
+	return result
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSearchRanksTextBeforeSource(t *testing.T) {
+	got := fixture().Search("frost", false, 0)
+	if want := []int{2, 1}; !equal(ids(got), want) {
+		t.Fatalf("expected %v, got %v", want, ids(got))
+	}
+	if got[1].Length != models.ShortQuotes {
+		t.Fatalf("expected the length group to be filled in, got %q", got[1].Length)
+	}
+}
+
+func TestSearchPhraseSpansLineBreaks(t *testing.T) {
+	if got := fixture().Search("WINDOWS every", false, 0); !equal(ids(got), []int{2}) {
+		t.Fatalf("expected quote 2, got %v", ids(got))
+	}
+}
+
+func TestSearchMatchesAllTermsAndTypos(t *testing.T) {
+	ix := fixture()
+	if got := ix.Search("einstein knowledge", false, 0); !equal(ids(got), []int{3}) {
+		t.Fatalf("expected terms across text and source to match, got %v", ids(got))
+	}
+	if got := ix.Search("knowlege", false, 0); !equal(ids(got), []int{3, 4}) {
+		t.Fatalf("expected a typo to match, got %v", ids(got))
+	}
+	if got := ix.Search("knowlege", true, 0); len(got) != 0 {
+		t.Fatalf("expected exact search to reject typos, got %v", ids(got))
+	}
+	if got := ix.Search("wey", false, 0); len(got) != 0 {
+		t.Fatalf("expected short terms to need an exact match, got %v", ids(got))
+	}
+}
+
+func TestSearchEmptyQueryAndLimit(t *testing.T) {
+	ix := fixture()
+	if got := ix.Search("  ", false, 0); !equal(ids(got), []int{1, 2, 3, 4}) {
+		t.Fatalf("expected every quote in id order, got %v", ids(got))
+	}
+	if got := ix.Search("", false, 2); len(got) != 2 {
+		t.Fatalf("expected the limit to apply, got %d", len(got))
+	}
+}
+
+func TestSearchEnglishQuotes(t *testing.T) {
+	quotes, err := loaders.LoadQuotes(models.English)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	got := NewIndex(quotes).Search("the glue that binds", false, 5)
+	if len(got) == 0 || got[0].rank != phraseInText {
+		t.Fatalf("expected a phrase match among the English quotes, got %+v", got)
+	}
+}
@@ -0,0 +1,225 @@
+package snippets
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+// LoadGo parses the Go files of the module rooted at opts.GoModule and
+// returns every top-level function, method and type declaration that fits
+// between opts.MinLines and opts.Lines. Test files, testdata, vendored code
+// and nested modules are skipped, as are files that cannot be read.
+// opts.GoPackages, when set, limits the packages read.
+func LoadGo(opts models.SourceOptions) ([]Snippet, error) {
+	root := opts.GoModule
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("snippets: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("snippets: %s is not a directory", root)
+	}
+	modulePath := readModulePath(root)
+
+	fset := token.NewFileSet()
+	var result []Snippet
+	err = filepath.WalkDir(root, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if file == root {
+				return err
+			}
+			// An unreadable file or directory only loses its own
+			// declarations.
+			if entry != nil && entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if file != root && (skipGoDir(name) || isModuleRoot(file)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !matchPackage(importPath(modulePath, path.Dir(rel)), opts.GoPackages) {
+			return nil
+		}
+
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return nil
+		}
+		if len(src) > maxFileSize {
+			return nil
+		}
+		parsed, err := parser.ParseFile(fset, file, src, parser.SkipObjectResolution)
+		if err != nil {
+			// Files that do not parse are not worth typing.
+			return nil
+		}
+		result = append(result, goDeclarations(fset, rel, src, parsed, opts)...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("snippets: %w", err)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("snippets: no Go declarations to type in %s", root)
+	}
+	return result, nil
+}
+
+// goDeclarations cuts the top-level functions, methods and types out of a
+// parsed file. rel names the file in snippet labels.
+func goDeclarations(fset *token.FileSet, rel string, src []byte, file *ast.File, opts models.SourceOptions) []Snippet {
+	var result []Snippet
+	for _, decl := range file.Decls {
+		var name string
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			name = funcName(decl)
+		case *ast.GenDecl:
+			if decl.Tok != token.TYPE {
+				continue
+			}
+			if len(decl.Specs) == 1 {
+				name = decl.Specs[0].(*ast.TypeSpec).Name.Name
+			}
+		default:
+			continue
+		}
+
+		start := fset.Position(decl.Pos())
+		end := fset.Position(decl.End())
+		if !fitsLines(end.Line-start.Line+1, opts) {
+			continue
+		}
+		qualified := file.Name.Name
+		if name != "" {
+			qualified += "." + name
+		}
+		lines := splitLines(string(src[start.Offset:end.Offset]))
+		result = append(result, Snippet{
+			Path:  rel,
+			Name:  qualified,
+			Start: start.Line,
+			End:   end.Line,
+			Text:  strings.Join(lines, "\n"),
+		})
+	}
+	return result
+}
+
+// funcName names a function, or a method after its receiver type.
+func funcName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return decl.Name.Name
+	}
+	recv := decl.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	switch recv := recv.(type) {
+	case *ast.IndexExpr:
+		return typeName(recv.X) + "." + decl.Name.Name
+	case *ast.IndexListExpr:
+		return typeName(recv.X) + "." + decl.Name.Name
+	default:
+		return typeName(recv) + "." + decl.Name.Name
+	}
+}
+
+func typeName(expr ast.Expr) string {
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return "?"
+}
+
+// skipGoDir reports whether the go command would ignore a directory.
+func skipGoDir(name string) bool {
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor"
+}
+
+// isModuleRoot reports whether dir holds a go.mod, making it a module of its
+// own rather than part of the one being walked.
+func isModuleRoot(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "go.mod"))
+	return err == nil
+}
+
+// readModulePath returns the module path declared in root/go.mod, or "" when
+// there is none.
+func readModulePath(root string) string {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module"); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+// importPath joins a module path and a slash-separated directory within it.
+// The standard library's "std" module has no prefix.
+func importPath(modulePath, dir string) string {
+	switch {
+	case dir == ".":
+		return modulePath
+	case modulePath == "" || modulePath == "std":
+		return dir
+	default:
+		return modulePath + "/" + dir
+	}
+}
+
+// matchPackage reports whether importPath is selected by patterns, which
+// work like the go command's: an exact import path, or a prefix followed by
+// "/..." that also matches everything below it. Without patterns every
+// package matches.
+func matchPackage(importPath string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+			if importPath == prefix || strings.HasPrefix(importPath, prefix+"/") {
+				return true
+			}
+			continue
+		}
+		if importPath == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// fitsLines reports whether a snippet of n lines falls within the configured
+// bounds.
+func fitsLines(n int, opts models.SourceOptions) bool {
+	return n >= opts.MinLines && n <= opts.Lines
+}
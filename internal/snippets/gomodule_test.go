@@ -0,0 +1,166 @@
+package snippets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+const shapesSource = `package shapes
+
+import "math"
+
+const Pi = math.Pi
+
+// Circle is round.
+type Circle struct {
+	Radius float64
+}
+
+func (c *Circle) Area() float64 {
+	return Pi * c.Radius * c.Radius
+}
+
+type (
+	Width  float64
+	Height float64
+)
+
+func New(r float64) *Circle { return &Circle{Radius: r} }
+`
+
+func TestLoadGoDeclarations(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod":                "module example.com/geo\n\ngo 1.22\n",
+		"shapes/shapes.go":      shapesSource,
+		"shapes/shapes_test.go": "package shapes\n\nfunc TestNothing() {\n}\n",
+		"testdata/skip.go":      "package skip\n\nfunc Skipped() {\n}\n",
+		"broken/broken.go":      "package broken\n\nfunc {\n",
+	})
+
+	got, err := LoadGo(models.SourceOptions{GoModule: root, Lines: 10, MinLines: 1})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	want := []string{
+		"shapes.Circle · shapes/shapes.go:8-10",
+		"shapes.Circle.Area · shapes/shapes.go:12-14",
+		"shapes · shapes/shapes.go:16-19",
+		"shapes.New · shapes/shapes.go:21-21",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d declarations, got %+v", len(want), got)
+	}
+	for i, label := range want {
+		if got[i].Label() != label {
+			t.Fatalf("declaration %d: expected %q, got %q", i, label, got[i].Label())
+		}
+	}
+	if got[1].Text != "func (c *Circle) Area() float64 {\n\treturn Pi * c.Radius * c.Radius\n}" {
+		t.Fatalf("unexpected method text %q", got[1].Text)
+	}
+
+	got, err = LoadGo(models.SourceOptions{GoModule: root, Lines: 10, MinLines: 3})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected one-line declarations to be filtered out, got %+v", got)
+	}
+}
+
+func TestLoadGoFiltersPackages(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod":               "module example.com/geo\n",
+		"shapes/shapes.go":     shapesSource,
+		"shapes/solid/cube.go": "package solid\n\nfunc Volume(s float64) float64 {\n\treturn s * s * s\n}\n",
+	})
+
+	got, err := LoadGo(models.SourceOptions{GoModule: root, GoPackages: []string{"example.com/geo/shapes/solid"}, Lines: 10, MinLines: 1})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "solid.Volume" {
+		t.Fatalf("expected only the solid package, got %+v", got)
+	}
+
+	got, err = LoadGo(models.SourceOptions{GoModule: root, GoPackages: []string{"example.com/geo/shapes/..."}, Lines: 10, MinLines: 1})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(got) != 5 {
+		t.Fatalf("expected shapes and its subpackages, got %d declarations", len(got))
+	}
+
+	if _, err := LoadGo(models.SourceOptions{GoModule: root, GoPackages: []string{"fmt"}, Lines: 10, MinLines: 1}); err == nil {
+		t.Fatal("expected an error when no package matches")
+	}
+}
+
+func TestLoadGoSkipsNestedModules(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod":                    "module example.com/geo\n",
+		"shapes/shapes.go":          shapesSource,
+		"tools/go.mod":              "module example.com/geo/tools\n",
+		"tools/gen/gen.go":          "package gen\n\nfunc Generate() {\n}\n",
+		"tools/gen/internal/own.go": "package internal\n\nfunc Own() {\n}\n",
+	})
+
+	got, err := LoadGo(models.SourceOptions{GoModule: root, Lines: 10, MinLines: 1})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	for _, snippet := range got {
+		if snippet.Name == "gen.Generate" || snippet.Name == "internal.Own" {
+			t.Fatalf("expected the nested tools module to be skipped, got %+v", snippet)
+		}
+	}
+	if len(got) != 4 {
+		t.Fatalf("expected only the shapes declarations, got %+v", got)
+	}
+}
+
+func TestLoadGoSkipsUnreadableFiles(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod":           "module example.com/geo\n",
+		"shapes/shapes.go": shapesSource,
+	})
+	if err := os.Symlink(filepath.Join(root, "missing.go"), filepath.Join(root, "shapes", "dangling.go")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+
+	got, err := LoadGo(models.SourceOptions{GoModule: root, Lines: 10, MinLines: 1})
+	if err != nil {
+		t.Fatalf("expected an unreadable file to be skipped, got %v", err)
+	}
+	if len(got) != 4 {
+		t.Fatalf("expected the readable declarations, got %+v", got)
+	}
+}
+
+func TestImportPathForStandardLibrary(t *testing.T) {
+	if got := importPath("std", "net/http"); got != "net/http" {
+		t.Fatalf("expected std packages to have no prefix, got %q", got)
+	}
+	if !matchPackage("net/http", []string{"net/..."}) || matchPackage("network", []string{"net/..."}) {
+		t.Fatal("unexpected match for net/...")
+	}
+}
@@ -0,0 +1,92 @@
+package snippets
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+// blockStyle describes how a language marks where a function ends.
+type blockStyle int
+
+const (
+	// braceBlocks end when the braces opened by the function balance out.
+	braceBlocks blockStyle = iota
+	// indentBlocks end at the first line indented no deeper than the start.
+	indentBlocks
+	// endBlocks end at an "end" keyword indented like the start.
+	endBlocks
+)
+
+type sourceLanguage struct {
+	language models.Language
+	style    blockStyle
+	// start matches the first line of a function, with leading whitespace
+	// removed. A nil start means the language has no functions to extract.
+	start *regexp.Regexp
+}
+
+var cLikeFunction = regexp.MustCompile(`^(?:[\w:<>\*&,\[\]]+\s+)+[\*&]*[\w:~]+\s*\([^;]*$`)
+
+// cLikeKeywords start lines that look like declarations but are statements.
+var cLikeKeywords = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "return": true, "else": true,
+	"catch": true, "do": true, "case": true, "new": true, "throw": true, "delete": true,
+	"sizeof": true, "using": true, "typedef": true, "await": true,
+}
+
+var sourceLanguages = map[string]sourceLanguage{
+	".go":   {models.Go, braceBlocks, regexp.MustCompile(`^func\b`)},
+	".rs":   {models.Rust, braceBlocks, regexp.MustCompile(`^(?:pub(?:\([^)]*\))?\s+)?(?:const\s+)?(?:async\s+)?(?:unsafe\s+)?fn\s+\w+`)},
+	".js":   {models.JavaScript, braceBlocks, jsFunction},
+	".jsx":  {models.JavaScript, braceBlocks, jsFunction},
+	".mjs":  {models.JavaScript, braceBlocks, jsFunction},
+	".ts":   {models.Typescript, braceBlocks, jsFunction},
+	".tsx":  {models.Typescript, braceBlocks, jsFunction},
+	".c":    {models.C, braceBlocks, cLikeFunction},
+	".h":    {models.C, braceBlocks, cLikeFunction},
+	".cc":   {models.Cpp, braceBlocks, cLikeFunction},
+	".cpp":  {models.Cpp, braceBlocks, cLikeFunction},
+	".hpp":  {models.Cpp, braceBlocks, cLikeFunction},
+	".cs":   {models.CSharp, braceBlocks, cLikeFunction},
+	".java": {models.Java, braceBlocks, cLikeFunction},
+	".kt":   {models.Kotlin, braceBlocks, regexp.MustCompile(`^(?:(?:public|private|protected|internal|override|open|suspend|inline|operator)\s+)*fun\b`)},
+	".php":  {models.PHP, braceBlocks, regexp.MustCompile(`^(?:(?:public|private|protected|static|abstract|final)\s+)*function\b`)},
+	".r":    {models.R, braceBlocks, regexp.MustCompile(`^[\w.]+\s*(?:<-|=)\s*function\s*\(`)},
+	".css":  {models.CSS, braceBlocks, nil},
+	".py":   {models.Python, indentBlocks, regexp.MustCompile(`^(?:async\s+)?def\s+\w+`)},
+	".rb":   {models.Ruby, endBlocks, regexp.MustCompile(`^def\s+`)},
+	".lua":  {models.Lua, endBlocks, regexp.MustCompile(`^(?:local\s+)?function\b`)},
+	".asm":  {models.Assembly, braceBlocks, nil},
+	".s":    {models.Assembly, braceBlocks, nil},
+}
+
+var jsFunction = regexp.MustCompile(`^(?:export\s+)?(?:default\s+)?(?:async\s+)?(?:function\b|(?:const|let)\s+\w+\s*=\s*(?:async\s*)?(?:function\b|\([^)]*\)\s*(?::\s*[^=]+)?=>))`)
+
+func lookupLanguage(path string) (sourceLanguage, bool) {
+	language, ok := sourceLanguages[strings.ToLower(filepath.Ext(path))]
+	return language, ok
+}
+
+// LanguageOf returns the code language of a source file by its extension.
+func LanguageOf(path string) (models.Language, bool) {
+	language, ok := lookupLanguage(path)
+	return language.language, ok
+}
+
+// startsFunction reports whether line, with indentation removed, begins a
+// function in language.
+func (l sourceLanguage) startsFunction(line string) bool {
+	if l.start == nil || !l.start.MatchString(line) {
+		return false
+	}
+	if l.start == cLikeFunction {
+		first := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == '('
+		})
+		return len(first) > 0 && !cLikeKeywords[first[0]]
+	}
+	return true
+}
@@ -0,0 +1,415 @@
+// Package snippets cuts real source files into passages to type: whole
+// functions where the language's functions can be found, and fixed-size line
+// windows otherwise.
+package snippets
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+// maxFileSize skips generated or minified files that are too large to be
+// worth practising on.
+const maxFileSize = 1 << 20
+
+// headerLines bounds how far a function signature may run before its body
+// opens.
+const headerLines = 5
+
+// skippedDirs are never descended into when walking a directory.
+var skippedDirs = map[string]bool{
+	"vendor":       true,
+	"node_modules": true,
+}
+
+// Snippet is a run of lines taken from a source file.
+type Snippet struct {
+	Path string
+	// Name is the declaration the snippet holds, e.g. "http.Client.Do", when
+	// it is known.
+	Name string
+	// Start and End are the 1-based line numbers of the first and last line.
+	Start int
+	End   int
+	Text  string
+}
+
+// Label identifies the snippet as path:start-end, after its name when it has
+// one.
+func (s Snippet) Label() string {
+	location := fmt.Sprintf("%s:%d-%d", s.Path, s.Start, s.End)
+	if s.Name == "" {
+		return location
+	}
+	return s.Name + " · " + location
+}
+
+// Language returns the code language of the file the snippet came from.
+func (s Snippet) Language() (models.Language, bool) {
+	return LanguageOf(s.Path)
+}
+
+// Load collects the files named by patterns and extracts their snippets.
+// Binary and very large files are skipped.
+func Load(patterns []string, opts models.SourceOptions) ([]Snippet, error) {
+	paths, err := Collect(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Snippet
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("snippets: %w", err)
+		}
+		if info.Size() > maxFileSize {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("snippets: %w", err)
+		}
+		if bytes.IndexByte(data, 0) >= 0 {
+			continue
+		}
+		result = append(result, Extract(path, string(data), opts)...)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("snippets: no code to type in %s", strings.Join(patterns, ", "))
+	}
+	return result, nil
+}
+
+// Collect expands patterns into a sorted list of files. A pattern may be a
+// file, a directory, which is walked for files in a known language, or a
+// glob.
+func Collect(patterns []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var result []string
+	add := func(path string) {
+		path = filepath.Clean(path)
+		if !seen[path] {
+			seen[path] = true
+			result = append(result, path)
+		}
+	}
+
+	for _, pattern := range patterns {
+		matches := []string{pattern}
+		if strings.ContainsAny(pattern, "*?[") {
+			var err error
+			matches, err = filepath.Glob(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("snippets: bad pattern %q: %w", pattern, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("snippets: no files match %q", pattern)
+			}
+		}
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, fmt.Errorf("snippets: %w", err)
+			}
+			if !info.IsDir() {
+				add(match)
+				continue
+			}
+			if err := walk(match, add); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("snippets: no source files found in %s", strings.Join(patterns, ", "))
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// walk calls add for every file under root in a known language, skipping
+// hidden and dependency directories.
+func walk(root string, add func(string)) error {
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if path != root && (strings.HasPrefix(name, ".") || skippedDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if _, ok := LanguageOf(name); ok && entry.Type().IsRegular() {
+			add(path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("snippets: %w", err)
+	}
+	return nil
+}
+
+// Extract cuts content, read from path, into snippets of at most opts.Lines
+// lines. Functions longer than that, or shorter than opts.MinLines, are
+// skipped; files without functions are split into line windows instead.
+func Extract(path, content string, opts models.SourceOptions) []Snippet {
+	if opts.Lines < 1 {
+		return nil
+	}
+	lines := splitLines(content)
+
+	if opts.Snippet != models.LineSnippets {
+		if language, ok := lookupLanguage(path); ok && language.start != nil {
+			if functions := extractFunctions(path, lines, language, opts); len(functions) > 0 {
+				return functions
+			}
+		}
+	}
+	return extractWindows(path, lines, opts.Lines)
+}
+
+func extractFunctions(path string, lines []string, language sourceLanguage, opts models.SourceOptions) []Snippet {
+	var result []Snippet
+	for i := 0; i < len(lines); i++ {
+		if !language.startsFunction(strings.TrimLeft(lines[i], " \t")) {
+			continue
+		}
+		end := language.functionEnd(lines, i)
+		if end < 0 {
+			continue
+		}
+		if fitsLines(end-i+1, opts) {
+			result = append(result, newSnippet(path, lines, i, end))
+		}
+		i = end
+	}
+	return result
+}
+
+func extractWindows(path string, lines []string, size int) []Snippet {
+	var result []Snippet
+	for from := 0; from < len(lines); from += size {
+		to := from + size - 1
+		if to >= len(lines) {
+			to = len(lines) - 1
+		}
+		for from <= to && lines[from] == "" {
+			from++
+		}
+		for to >= from && lines[to] == "" {
+			to--
+		}
+		if from <= to {
+			result = append(result, newSnippet(path, lines, from, to))
+		}
+	}
+	return result
+}
+
+func newSnippet(path string, lines []string, from, to int) Snippet {
+	return Snippet{
+		Path:  path,
+		Start: from + 1,
+		End:   to + 1,
+		Text:  dedent(lines[from : to+1]),
+	}
+}
+
+// splitLines unifies line endings and strips trailing whitespace, which
+// cannot be seen on screen.
+func splitLines(content string) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r", "\n")
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return lines
+}
+
+// dedent removes the indentation shared by every non-blank line, so methods
+// and nested functions start at the left edge.
+func dedent(lines []string) string {
+	prefix := ""
+	first := true
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		indent := leadingSpace(line)
+		if first {
+			prefix, first = indent, false
+			continue
+		}
+		for !strings.HasPrefix(indent, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		result[i] = strings.TrimPrefix(line, prefix)
+	}
+	return strings.Join(result, "\n")
+}
+
+func leadingSpace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// indentWidth measures indentation, counting a tab as four columns.
+func indentWidth(line string) int {
+	width := 0
+	for _, r := range leadingSpace(line) {
+		if r == '\t' {
+			width += 4
+		} else {
+			width++
+		}
+	}
+	return width
+}
+
+// functionEnd returns the index of the last line of the function starting at
+// lines[start], or -1 when its end cannot be found.
+func (l sourceLanguage) functionEnd(lines []string, start int) int {
+	switch l.style {
+	case indentBlocks:
+		return indentBlockEnd(lines, start)
+	case endBlocks:
+		return endKeywordEnd(lines, start)
+	default:
+		return l.braceBlockEnd(lines, start)
+	}
+}
+
+func (l sourceLanguage) braceBlockEnd(lines []string, start int) int {
+	depth := 0
+	opened := false
+	for i := start; i < len(lines); i++ {
+		for _, r := range l.stripLiterals(lines[i]) {
+			switch r {
+			case '{':
+				depth++
+				opened = true
+			case '}':
+				depth--
+			case ';':
+				if !opened {
+					// A declaration without a body.
+					return -1
+				}
+			}
+		}
+		if opened && depth <= 0 {
+			return i
+		}
+		if !opened && i-start >= headerLines {
+			return -1
+		}
+	}
+	return -1
+}
+
+// stripLiterals drops string and character literals and line comments so
+// the braces inside them are not counted.
+func (l sourceLanguage) stripLiterals(line string) string {
+	// Rust lifetimes ('a) use a lone quote, so only character literals
+	// count there.
+	singleQuoteStrings := l.language != models.Rust
+	hashComments := l.language == models.PHP || l.language == models.R
+	var builder strings.Builder
+	var quote rune
+	escaped := false
+	runes := []rune(line)
+	for i, r := range runes {
+		switch {
+		case quote != 0:
+			if escaped {
+				escaped = false
+			} else if r == '\\' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '`':
+			quote = r
+		case r == '\'':
+			if singleQuoteStrings || isCharLiteral(runes[i:]) {
+				quote = r
+			} else {
+				builder.WriteRune(r)
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			return builder.String()
+		case r == '#' && hashComments:
+			return builder.String()
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// isCharLiteral reports whether runes open with a character literal such as
+// 'x' or '\n'.
+func isCharLiteral(runes []rune) bool {
+	if len(runes) > 1 && runes[1] == '\\' {
+		return true
+	}
+	return len(runes) > 2 && runes[2] == '\''
+}
+
+func indentBlockEnd(lines []string, start int) int {
+	base := indentWidth(lines[start])
+	end := start
+	for i := start + 1; i < len(lines); i++ {
+		line := lines[i]
+		if line == "" {
+			continue
+		}
+		if indentWidth(line) <= base && !strings.HasPrefix(strings.TrimLeft(line, " \t"), ")") {
+			break
+		}
+		end = i
+	}
+	if end == start {
+		return -1
+	}
+	return end
+}
+
+func endKeywordEnd(lines []string, start int) int {
+	base := indentWidth(lines[start])
+	for i := start + 1; i < len(lines); i++ {
+		line := lines[i]
+		if line == "" {
+			continue
+		}
+		width := indentWidth(line)
+		if width < base {
+			return -1
+		}
+		if width == base {
+			trimmed := strings.TrimLeft(line, " \t")
+			if trimmed == "end" || strings.HasPrefix(trimmed, "end ") || strings.HasPrefix(trimmed, "end)") {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,150 @@
+package snippets
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+const goSource = `package demo
+
+// Add sums two numbers.
+func Add(a, b int) int {
+	return a + b
+}
+
+type box struct{ open bool }
+
+func (b *box) Toggle() {
+	if b.open {
+		b.open = false
+		return
+	}
+	b.open = true
+}
+
+func Long() {
+	one()
+	two()
+	three()
+	four()
+	five()
+	six()
+}
+`
+
+func TestExtractGoFunctions(t *testing.T) {
+	got := Extract("demo.go", goSource, models.SourceOptions{Snippet: models.FunctionSnippets, Lines: 7})
+	if len(got) != 2 {
+		t.Fatalf("expected 2 functions, got %d: %+v", len(got), got)
+	}
+	if got[0].Text != "func Add(a, b int) int {\n\treturn a + b\n}" {
+		t.Fatalf("unexpected first function %q", got[0].Text)
+	}
+	if label := got[1].Label(); label != "demo.go:10-16" {
+		t.Fatalf("unexpected label %q", label)
+	}
+}
+
+func TestExtractIgnoresBracesInLiterals(t *testing.T) {
+	source := "fn parse<'a>(s: &'a str) -> &'a str {\n    let open = \"{\";\n    s // }\n}\n"
+	got := Extract("lib.rs", source, models.SourceOptions{Lines: 10})
+	if len(got) != 1 || got[0].End != 4 {
+		t.Fatalf("expected one function ending on line 4, got %+v", got)
+	}
+}
+
+func TestExtractDedentsMethods(t *testing.T) {
+	source := "class Greeter:\n    def greet(self, name):\n        if name:\n            return 'hi ' + name\n\n        return 'hi'\n\n    x = 1\n"
+	got := Extract("greeter.py", source, models.SourceOptions{Lines: 10})
+	want := "def greet(self, name):\n    if name:\n        return 'hi ' + name\n\n    return 'hi'"
+	if len(got) != 1 || got[0].Text != want {
+		t.Fatalf("unexpected snippets %+v", got)
+	}
+}
+
+func TestExtractRubyEndBlocks(t *testing.T) {
+	source := "def hello(name)\n  if name\n    puts name\n  end\nend\n"
+	got := Extract("hello.rb", source, models.SourceOptions{Lines: 10})
+	if len(got) != 1 || got[0].Start != 1 || got[0].End != 5 {
+		t.Fatalf("unexpected snippets %+v", got)
+	}
+}
+
+func TestExtractLineWindows(t *testing.T) {
+	got := Extract("demo.go", goSource, models.SourceOptions{Snippet: models.LineSnippets, Lines: 3})
+	if len(got) < 3 {
+		t.Fatalf("expected line windows, got %+v", got)
+	}
+	if got[2].Start != 8 || got[2].End != 8 || got[2].Text != "type box struct{ open bool }" {
+		t.Fatalf("expected blank edges to be trimmed, got %+v", got[2])
+	}
+	for _, snippet := range got {
+		if lines := strings.Count(snippet.Text, "\n") + 1; lines > 3 {
+			t.Fatalf("window %s has %d lines", snippet.Label(), lines)
+		}
+	}
+}
+
+func TestExtractFallsBackToWindows(t *testing.T) {
+	got := Extract("style.css", "body {\n  margin: 0;\n}\n", models.SourceOptions{Lines: 15})
+	if len(got) != 1 || got[0].Text != "body {\n  margin: 0;\n}" {
+		t.Fatalf("unexpected snippets %+v", got)
+	}
+}
+
+func TestCollectWalksDirectoriesAndGlobs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.go", "b.py", "notes.txt", "vendor/dep.go", ".git/hook.go", "sub/c.go"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("package x\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := Collect([]string{dir, filepath.Join(dir, "*.go"), filepath.Join(dir, "notes.txt")})
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	want := []string{
+		filepath.Join(dir, "a.go"),
+		filepath.Join(dir, "b.py"),
+		filepath.Join(dir, "notes.txt"),
+		filepath.Join(dir, "sub", "c.go"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if _, err := Collect([]string{filepath.Join(dir, "*.rs")}); err == nil {
+		t.Fatal("expected an error for a glob without matches")
+	}
+}
+
+func TestLoadSkipsBinaryFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "blob.go"), []byte("func x() {\x00}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load([]string{dir}, models.SourceOptions{Lines: 15}); err == nil {
+		t.Fatal("expected an error when only binary files are found")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "ok.go"), []byte(goSource), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load([]string{dir}, models.SourceOptions{Lines: 15})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(got) != 3 || got[0].Path != filepath.Join(dir, "ok.go") {
+		t.Fatalf("unexpected snippets %+v", got)
+	}
+}
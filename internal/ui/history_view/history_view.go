@@ -0,0 +1,111 @@
+package history_view
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/neilsmahajan/typing-test-tui/internal/history"
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+	"github.com/neilsmahajan/typing-test-tui/internal/ui/theme"
+)
+
+const (
+	defaultTableHeight = 15
+	// Rows left over for the header, subtitle and help line.
+	chromeHeight = 6
+)
+
+type Model struct {
+	table  table.Model
+	count  int
+	styles theme.Styles
+	title  string
+}
+
+func InitialModel(title string, results []models.Result) Model {
+	styles := theme.DefaultStyles()
+
+	columns := make([]table.Column, len(history.Headers))
+	rows := make([]table.Row, len(results))
+	for i, header := range history.Headers {
+		columns[i] = table.Column{Title: header, Width: lipgloss.Width(header)}
+	}
+	for i, result := range results {
+		row := history.Row(result)
+		for j, cell := range row {
+			if width := lipgloss.Width(cell); width > columns[j].Width {
+				columns[j].Width = width
+			}
+		}
+		rows[i] = row
+	}
+
+	height := defaultTableHeight
+	if len(rows) < height {
+		height = len(rows) + 1
+	}
+
+	tableStyles := table.DefaultStyles()
+	tableStyles.Header = tableStyles.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		BorderBottom(true).
+		Foreground(lipgloss.Color("245")).
+		Bold(true)
+	tableStyles.Selected = tableStyles.Selected.
+		Foreground(lipgloss.Color("0")).
+		Background(lipgloss.Color("218")).
+		Bold(false)
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithFocused(true),
+		table.WithHeight(height),
+		table.WithStyles(tableStyles),
+	)
+
+	return Model{
+		table:  t,
+		count:  len(results),
+		styles: styles,
+		title:  title,
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages (key presses, etc.)
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		if height := msg.Height - chromeHeight; height > 0 && height < m.table.Height() {
+			m.table.SetHeight(height)
+		}
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+// View defines UI rendering
+func (m Model) View() string {
+	sections := []string{
+		m.styles.Header.Render(m.title),
+		m.styles.Subtitle.Render(fmt.Sprintf("%d results", m.count)),
+		m.styles.QuoteBox.Padding(0, 1).Render(m.table.View()),
+		m.styles.Instruction.Render("↑/↓: scroll • g/G: top/bottom • q: quit"),
+	}
+	return "\n" + m.styles.Container.Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+}
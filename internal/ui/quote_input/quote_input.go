@@ -20,40 +20,100 @@ type Model struct {
 	Target string
 	// what user has currentText so far
 	currentText      textarea.Model
-	languageQuotes   models.LanguageQuotes
-	rng              *rand.Rand
+	language         models.Language
+	source           Source
+	passage          Passage
+	header           string
+	prompt           string
 	viewportWidth    int
 	styles           theme.Styles
 	session          typing.Session
 	newlineIndicator string
 }
 
-func InitialModel(languageQuotes models.LanguageQuotes) Model {
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	quote := randomQuote(languageQuotes, rng)
-	styles := theme.DefaultStyles()
-	session := typing.NewSession()
+// Passage is one text to type together with what identifies it.
+type Passage struct {
+	Text    string
+	QuoteID int
+	// Label describes where the text came from, e.g. "notes.txt · chunk 2/5".
+	Label string
+	// Attribution credits the author or work, shown once the test is done.
+	Attribution string
+}
+
+// Source supplies the passage for each new test.
+type Source interface {
+	Next() Passage
+}
+
+// Options configures a Model that types passages from a Source other than
+// the built-in quote collection.
+type Options struct {
+	Header           string
+	Prompt           string
+	Language         models.Language
+	NewlineIndicator string
+	Source           Source
+}
+
+func InitialModel(languageQuotes models.LanguageQuotes, sessionOptions typing.SessionOptions) Model {
 	indicator := ""
 	if strings.HasPrefix(string(languageQuotes.Language), "code_") {
 		indicator = typing.DefaultNewlineIndicator
 	}
 
+	return NewModel(Options{
+		Header:           "Quote Mode",
+		Prompt:           "Press Enter for another quote or Ctrl+C to exit.",
+		Language:         languageQuotes.Language,
+		NewlineIndicator: indicator,
+		Source:           NewQuoteSource(languageQuotes),
+	}, sessionOptions)
+}
+
+func NewModel(opts Options, sessionOptions typing.SessionOptions) Model {
+	passage := opts.Source.Next()
+	styles := theme.DefaultStyles()
+	session := typing.NewSessionWithOptions(sessionOptions)
+	session.SetQuoteID(passage.QuoteID)
+
 	ti := textarea.New()
-	ti.Placeholder = quote.Text
+	ti.Placeholder = passage.Text
 	ti.SetWidth(typing.DefaultBoxWidth)
 	ti.Focus()
 
 	return Model{
-		Target:           quote.Text,
+		Target:           passage.Text,
 		currentText:      ti,
-		languageQuotes:   languageQuotes,
-		rng:              rng,
+		language:         opts.Language,
+		source:           opts.Source,
+		passage:          passage,
+		header:           opts.Header,
+		prompt:           opts.Prompt,
 		styles:           styles,
 		session:          session,
-		newlineIndicator: indicator,
+		newlineIndicator: opts.NewlineIndicator,
 	}
 }
 
+type quoteSource struct {
+	quotes models.LanguageQuotes
+	rng    *rand.Rand
+}
+
+// NewQuoteSource picks a random quote from languageQuotes for every test.
+func NewQuoteSource(languageQuotes models.LanguageQuotes) Source {
+	return &quoteSource{
+		quotes: languageQuotes,
+		rng:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (s *quoteSource) Next() Passage {
+	quote := randomQuote(s.quotes, s.rng)
+	return Passage{Text: quote.Text, QuoteID: quote.ID, Attribution: quote.Source}
+}
+
 func randomQuote(languageQuotes models.LanguageQuotes, rng *rand.Rand) models.Quote {
 	count := len(languageQuotes.Quotes)
 	if count == 0 {
@@ -69,10 +129,18 @@ func (m Model) Init() tea.Cmd {
 
 // Update handles messages (key presses, etc.)
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+	return m, tea.Batch(cmd, m.session.SaveCmd())
+}
+
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmd tea.Cmd
 	prevValue := m.currentText.Value()
 
 	switch msg := msg.(type) {
+	case typing.SavedMsg:
+		m.session.Saved(msg)
+		return m, nil
 	case tea.WindowSizeMsg:
 		m.viewportWidth = msg.Width
 		metrics := typing.ComputeBoxMetrics(m.Target, m.styles, m.viewportWidth)
@@ -86,8 +154,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case tea.KeyEnter:
 				m.session.Reset()
 				m.currentText.SetValue("")
-				quote := randomQuote(m.languageQuotes, m.rng)
-				m.Target = quote.Text
+				m.passage = m.source.Next()
+				m.session.SetQuoteID(m.passage.QuoteID)
+				m.Target = m.passage.Text
 				m.currentText.Placeholder = m.Target
 				metrics := typing.ComputeBoxMetrics(m.Target, m.styles, m.viewportWidth)
 				m.currentText.SetWidth(metrics.ContentWidth)
@@ -104,10 +173,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case tea.KeyTab:
 			if !m.session.Finished() {
-				m.session.Finish(time.Now(), normalizeTypedValue(m.currentText.Value(), m.Target))
+				m.session.Abandon(time.Now(), normalizeTypedValue(m.currentText.Value(), m.Target), m.Target)
 			}
 			return m, nil
 		}
+
+		if m.session.Rejects(time.Now(), msg, normalizeTypedValue(m.currentText.Value(), m.Target), m.Target) {
+			return m, nil
+		}
 	case error:
 		return m, nil
 	}
@@ -118,31 +191,33 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	m.currentText, cmd = m.currentText.Update(msg)
 
+	// Track the key before any indentation is inserted for it, so the
+	// inserted runes are not logged as keystrokes.
+	typedNormalized := normalizeTypedValue(m.currentText.Value(), m.Target)
+	now := time.Now()
+	if !m.session.Started() && typedNormalized != "" {
+		m.session.Start(now)
+	}
+	m.session.Track(now, normalizeTypedValue(prevValue, m.Target), typedNormalized, m.Target)
+
 	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.Type == tea.KeyEnter && !m.session.Finished() {
 		prevNormalized := normalizeTypedValue(prevValue, m.Target)
-		currentNormalized := normalizeTypedValue(m.currentText.Value(), m.Target)
 
-		if len([]rune(currentNormalized)) > len([]rune(prevNormalized)) {
+		if len([]rune(typedNormalized)) > len([]rune(prevNormalized)) {
 			targetRunes := []rune(m.Target)
-			currentRunes := []rune(currentNormalized)
+			currentRunes := []rune(typedNormalized)
 			if prefixMatches(targetRunes, currentRunes) {
 				if indent := indentAfter(targetRunes, len(currentRunes)); indent != "" {
 					m.currentText.InsertString(indent)
+					typedNormalized = normalizeTypedValue(m.currentText.Value(), m.Target)
 				}
 			}
 		}
-
-	}
-
-	typedNormalized := normalizeTypedValue(m.currentText.Value(), m.Target)
-
-	if !m.session.Started() && typedNormalized != "" {
-		m.session.Start(time.Now())
 	}
 
 	// check if completed (capture finish time & wpm only once)
 	if !m.session.Finished() && typedNormalized == m.Target {
-		m.session.Finish(time.Now(), m.Target)
+		m.session.Finish(now, typedNormalized, m.Target)
 	}
 
 	return m, cmd
@@ -166,6 +241,11 @@ func (m Model) View() string {
 			ViewportWidth:    m.viewportWidth,
 			NewlineIndicator: m.newlineIndicator,
 		}),
+	}
+	if attribution := m.renderAttribution(metrics.OuterWidth); attribution != "" {
+		sections = append(sections, attribution)
+	}
+	sections = append(sections,
 		typing.RenderStats(typing.StatsConfig{
 			Target:  m.Target,
 			Typed:   typed,
@@ -174,7 +254,7 @@ func (m Model) View() string {
 			Session: &m.session,
 			Now:     now,
 		}),
-	}
+	)
 
 	if m.session.Finished() {
 		sections = append(sections, typing.RenderCompletion(typing.CompletionConfig{
@@ -182,7 +262,7 @@ func (m Model) View() string {
 			Styles:  m.styles,
 			Session: &m.session,
 			Now:     now,
-			Prompt:  "Press Enter for another quote or Ctrl+C to exit.",
+			Prompt:  m.prompt,
 		}))
 	} else {
 		sections = append(sections, typing.RenderInstructions(typing.InstructionsConfig{
@@ -196,14 +276,29 @@ func (m Model) View() string {
 }
 
 func (m Model) renderHeader(width int) string {
-	return m.styles.Header.MaxWidth(width).Render("Quote Mode")
+	return m.styles.Header.MaxWidth(width).Render(m.header)
+}
+
+// renderAttribution credits the quote's source once it has been typed, so
+// it does not give the text away beforehand.
+func (m Model) renderAttribution(width int) string {
+	if !m.session.Finished() || m.passage.Attribution == "" {
+		return ""
+	}
+	return m.styles.Attribution.Width(width).Render("— " + m.passage.Attribution)
 }
 
 func (m Model) renderSubtitle(width int) string {
-	languageName := typing.DisplayLanguage(m.languageQuotes.Language)
+	languageName := typing.DisplayLanguage(m.language)
 	words := typing.WordCount(m.Target)
 	chars := utf8.RuneCountInString(m.Target)
 	info := fmt.Sprintf("Language: %s · %d words · %d chars", languageName, words, chars)
+	if m.passage.Label != "" {
+		info = m.passage.Label + " · " + info
+	}
+	if best := typing.PersonalBestLabel(&m.session); best != "" {
+		info += " · " + best
+	}
 	return m.styles.Subtitle.MaxWidth(width).Render(info)
 }
 
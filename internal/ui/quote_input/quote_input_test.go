@@ -1,9 +1,11 @@
 package quote_input
 
 import (
+	"strings"
 	"testing"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/neilsmahajan/typing-test-tui/internal/models"
 	"github.com/neilsmahajan/typing-test-tui/internal/ui/typing"
 )
@@ -40,7 +42,7 @@ func TestInitialModelSetsIndicators(t *testing.T) {
 		Language: models.Go,
 		Quotes:   []models.Quote{{Text: "code sample"}},
 	}
-	codeModel := InitialModel(codeQuotes)
+	codeModel := InitialModel(codeQuotes, typing.SessionOptions{})
 	if codeModel.newlineIndicator != typing.DefaultNewlineIndicator {
 		t.Fatalf("expected newline indicator for code language")
 	}
@@ -49,7 +51,7 @@ func TestInitialModelSetsIndicators(t *testing.T) {
 		Language: models.English,
 		Quotes:   []models.Quote{{Text: "hello world"}},
 	}
-	plainModel := InitialModel(plainQuotes)
+	plainModel := InitialModel(plainQuotes, typing.SessionOptions{})
 	if plainModel.newlineIndicator != "" {
 		t.Fatalf("expected no newline indicator for natural language")
 	}
@@ -66,7 +68,109 @@ func TestModelUpdateIgnoresErrors(t *testing.T) {
 		Language: models.English,
 		Quotes:   []models.Quote{{Text: "hello"}},
 	}
-	model := InitialModel(codeQuotes)
+	model := InitialModel(codeQuotes, typing.SessionOptions{})
 	// Should not panic when receiving an unexpected message type
 	model.Update(time.Now())
 }
+
+type sliceSource struct {
+	passages []Passage
+	next     int
+}
+
+func (s *sliceSource) Next() Passage {
+	passage := s.passages[s.next%len(s.passages)]
+	s.next++
+	return passage
+}
+
+func TestNewModelAdvancesSource(t *testing.T) {
+	source := &sliceSource{passages: []Passage{
+		{Text: "hi", Label: "notes.txt · chunk 1/2"},
+		{Text: "there", Label: "notes.txt · chunk 2/2"},
+	}}
+	model := NewModel(Options{Header: "Custom Mode", Language: models.English, Source: source}, typing.SessionOptions{})
+	if model.Target != "hi" || !strings.Contains(model.renderSubtitle(80), "chunk 1/2") {
+		t.Fatalf("expected first passage with its label, got %q", model.Target)
+	}
+
+	for _, r := range "hi" {
+		updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		model = updated.(Model)
+	}
+	if !model.session.Finished() {
+		t.Fatalf("expected passage to finish once typed")
+	}
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(Model)
+	if model.Target != "there" || !strings.Contains(model.renderSubtitle(80), "chunk 2/2") {
+		t.Fatalf("expected Enter to load the next passage, got %q", model.Target)
+	}
+}
+
+func TestAttributionShownAfterCompletion(t *testing.T) {
+	quotes := models.LanguageQuotes{
+		Language: models.English,
+		Quotes:   []models.Quote{{ID: 7, Text: "hi", Source: "Greeting Cards"}},
+	}
+	updated, _ := InitialModel(quotes, typing.SessionOptions{}).Update(tea.WindowSizeMsg{Width: 100})
+	model := updated.(Model)
+	if strings.Contains(model.View(), "Greeting Cards") {
+		t.Fatalf("expected the source to stay hidden while typing")
+	}
+
+	for _, r := range "hi" {
+		updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		model = updated.(Model)
+	}
+	if !strings.Contains(model.View(), "— Greeting Cards") {
+		t.Fatalf("expected the source after completion")
+	}
+	if id := model.session.Result().QuoteID; id != 7 {
+		t.Fatalf("expected the quote id in the result, got %d", id)
+	}
+}
+
+func TestRejectedKeysNeverReachTheTextArea(t *testing.T) {
+	source := &sliceSource{passages: []Passage{{Text: "ab cd"}}}
+	model := NewModel(Options{Header: "Custom Mode", Language: models.English, Source: source}, typing.SessionOptions{
+		StopOnError: models.StopOnLetter,
+		Confidence:  models.ConfidenceOn,
+	})
+
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune{'a'}},
+		{Type: tea.KeyRunes, Runes: []rune{'x'}},
+		{Type: tea.KeyRunes, Runes: []rune{'b'}},
+		{Type: tea.KeySpace, Runes: []rune{' '}},
+		{Type: tea.KeyBackspace},
+	} {
+		updated, _ := model.Update(msg)
+		model = updated.(Model)
+	}
+	if got := model.currentText.Value(); got != "ab " {
+		t.Fatalf("expected the wrong key and the backspace into the last word to be dropped, got %q", got)
+	}
+}
+
+func TestAutoIndentIsNotLoggedAsKeystrokes(t *testing.T) {
+	source := &sliceSource{passages: []Passage{{Text: "{\n    x}"}}}
+	model := NewModel(Options{Header: "Custom Mode", Language: models.Go, Source: source}, typing.SessionOptions{})
+
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune{'{'}},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyRunes, Runes: []rune{'x'}},
+		{Type: tea.KeyRunes, Runes: []rune{'}'}},
+	} {
+		updated, _ := model.Update(msg)
+		model = updated.(Model)
+	}
+	if !model.session.Finished() {
+		t.Fatalf("expected the indented passage to finish, got %q", model.currentText.Value())
+	}
+	if got := model.session.KeystrokeCount(); got != 4 {
+		t.Fatalf("expected only the 4 keys pressed to be logged, got %d", got)
+	}
+}
@@ -0,0 +1,204 @@
+package quote_picker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+	"github.com/neilsmahajan/typing-test-tui/internal/quotesearch"
+	"github.com/neilsmahajan/typing-test-tui/internal/ui/theme"
+)
+
+const (
+	defaultTableHeight = 15
+	// Rows left over for the header, subtitle, search box and help line.
+	chromeHeight = 9
+	// Room taken by the box border, padding and column gaps around the table.
+	tableChrome        = 14
+	defaultQuoteWidth  = 50
+	minQuoteWidth      = 20
+	instructionMessage = "Type to search • ↑/↓: select • Enter: type quote • Esc: quit"
+)
+
+// Options configures the picker.
+type Options struct {
+	Title    string
+	Language models.Language
+	Query    string
+	// Exact turns off typo-tolerant matching.
+	Exact bool
+	// Limit caps the number of matches listed; zero lists every match.
+	Limit int
+}
+
+// Model searches the quotes of a language as the user types and remembers
+// the quote picked with Enter.
+type Model struct {
+	index   *quotesearch.Index
+	opts    Options
+	input   textinput.Model
+	table   table.Model
+	matches []quotesearch.Match
+	styles  theme.Styles
+	chosen  *models.Quote
+}
+
+func InitialModel(languageQuotes models.LanguageQuotes, opts Options) Model {
+	input := textinput.New()
+	input.Prompt = "Search: "
+	input.Placeholder = "words from the quote or its source"
+	input.SetValue(opts.Query)
+	input.Focus()
+
+	tableStyles := table.DefaultStyles()
+	tableStyles.Header = tableStyles.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		BorderBottom(true).
+		Foreground(lipgloss.Color("245")).
+		Bold(true)
+	tableStyles.Selected = tableStyles.Selected.
+		Foreground(lipgloss.Color("0")).
+		Background(lipgloss.Color("218")).
+		Bold(false)
+
+	t := table.New(
+		table.WithColumns(columns(defaultQuoteWidth)),
+		table.WithFocused(true),
+		table.WithHeight(defaultTableHeight),
+		table.WithStyles(tableStyles),
+	)
+
+	m := Model{
+		index:  quotesearch.NewIndex(languageQuotes),
+		opts:   opts,
+		input:  input,
+		table:  t,
+		styles: theme.DefaultStyles(),
+	}
+	m.search()
+	return m
+}
+
+// Chosen returns the quote picked with Enter, if any.
+func (m Model) Chosen() (models.Quote, bool) {
+	if m.chosen == nil {
+		return models.Quote{}, false
+	}
+	return *m.chosen, true
+}
+
+func (m Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles messages (key presses, etc.)
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.table.SetColumns(columns(max(msg.Width-tableChrome-fixedWidth(), minQuoteWidth)))
+		m.table.SetRows(m.rows())
+		if height := msg.Height - chromeHeight; height > 0 {
+			m.table.SetHeight(min(height, defaultTableHeight))
+		}
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			return m, tea.Quit
+		case "enter":
+			if len(m.matches) == 0 {
+				return m, nil
+			}
+			quote := m.matches[m.table.Cursor()].Quote
+			m.chosen = &quote
+			return m, tea.Quit
+		case "up", "down", "pgup", "pgdown":
+			var cmd tea.Cmd
+			m.table, cmd = m.table.Update(msg)
+			return m, cmd
+		}
+	}
+
+	previous := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != previous {
+		m.search()
+	}
+	return m, cmd
+}
+
+// View defines UI rendering
+func (m Model) View() string {
+	count := fmt.Sprintf("%d of %d quotes", len(m.matches), m.index.Len())
+	if m.opts.Limit > 0 && len(m.matches) == m.opts.Limit {
+		count = fmt.Sprintf("first %d matches of %d quotes", len(m.matches), m.index.Len())
+	}
+	sections := []string{
+		m.styles.Header.Render(m.opts.Title),
+		m.styles.Subtitle.Render(count),
+		m.input.View(),
+		m.styles.QuoteBox.Padding(0, 1).Render(m.table.View()),
+		m.styles.Instruction.Render(instructionMessage),
+	}
+	return "\n" + m.styles.Container.Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+}
+
+func (m *Model) search() {
+	m.matches = m.index.Search(m.input.Value(), m.opts.Exact, m.opts.Limit)
+	m.table.SetRows(m.rows())
+	m.table.GotoTop()
+}
+
+func (m Model) rows() []table.Row {
+	quoteWidth := m.table.Columns()[len(m.table.Columns())-1].Width
+	rows := make([]table.Row, len(m.matches))
+	for i, match := range m.matches {
+		rows[i] = table.Row{
+			strconv.Itoa(match.Quote.ID),
+			string(match.Length),
+			strconv.Itoa(match.Quote.Length),
+			Excerpt(match.Quote.Source, sourceWidth),
+			Excerpt(match.Quote.Text, quoteWidth),
+		}
+	}
+	return rows
+}
+
+const (
+	idWidth     = 5
+	lengthWidth = 6
+	charsWidth  = 5
+	sourceWidth = 24
+)
+
+func columns(quoteWidth int) []table.Column {
+	return []table.Column{
+		{Title: "ID", Width: idWidth},
+		{Title: "Length", Width: lengthWidth},
+		{Title: "Chars", Width: charsWidth},
+		{Title: "Source", Width: sourceWidth},
+		{Title: "Quote", Width: quoteWidth},
+	}
+}
+
+func fixedWidth() int {
+	return idWidth + lengthWidth + charsWidth + sourceWidth
+}
+
+// Excerpt squeezes text onto one line of at most width cells, ending it with
+// an ellipsis when it had to be cut.
+func Excerpt(text string, width int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if width <= 0 || len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,72 @@
+package quote_picker
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+func fixture() models.LanguageQuotes {
+	return models.LanguageQuotes{
+		Language: models.English,
+		Groups:   [][2]int{{0, 100}, {101, 300}, {301, 600}, {601, 9999}},
+		Quotes: []models.Quote{
+			{ID: 1, Text: "The only way out is through.", Source: "Robert Frost", Length: 28},
+			{ID: 2, Text: "Imagination is more important than knowledge.", Source: "Albert Einstein", Length: 45},
+			{ID: 3, Text: "Knowledge speaks, but wisdom listens.", Source: "Jimi Hendrix", Length: 37},
+		},
+	}
+}
+
+func typeRunes(m Model, text string) Model {
+	for _, r := range text {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	return m
+}
+
+func TestTypingNarrowsAndEnterChooses(t *testing.T) {
+	m := InitialModel(fixture(), Options{Title: "Quotes"})
+	if len(m.matches) != 3 {
+		t.Fatalf("expected every quote before searching, got %d", len(m.matches))
+	}
+
+	m = typeRunes(m, "knowledge")
+	if len(m.matches) != 2 {
+		t.Fatalf("expected two matches, got %d", len(m.matches))
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, cmd := updated.(Model).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	quote, ok := m.Chosen()
+	if !ok || quote.ID != 3 {
+		t.Fatalf("expected quote 3 to be chosen, got %+v (%t)", quote, ok)
+	}
+	if cmd == nil {
+		t.Fatal("expected choosing a quote to quit the picker")
+	}
+}
+
+func TestEscQuitsWithoutChoosing(t *testing.T) {
+	m := InitialModel(fixture(), Options{Title: "Quotes", Query: "zzqxv"})
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if _, ok := updated.(Model).Chosen(); ok {
+		t.Fatal("expected Enter without matches to choose nothing")
+	}
+	updated, cmd := updated.(Model).Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if _, ok := updated.(Model).Chosen(); ok || cmd == nil {
+		t.Fatal("expected Esc to quit without a quote")
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	if got := Excerpt("one\n two   three", 20); got != "one two three" {
+		t.Fatalf("expected whitespace to be squeezed, got %q", got)
+	}
+	if got := Excerpt("abcdefgh", 5); got != "abcd…" {
+		t.Fatalf("expected an ellipsis, got %q", got)
+	}
+}
@@ -8,6 +8,7 @@ type Styles struct {
 	Subtitle      lipgloss.Style
 	QuoteBox      lipgloss.Style
 	QuoteContent  lipgloss.Style
+	Attribution   lipgloss.Style
 	Instruction   lipgloss.Style
 	StatsRow      lipgloss.Style
 	StatBlock     lipgloss.Style
@@ -15,10 +16,17 @@ type Styles struct {
 	StatValue     lipgloss.Style
 	StatSeparator string
 	Success       lipgloss.Style
+	Failure       lipgloss.Style
+	PersonalBest  lipgloss.Style
 	Typed         lipgloss.Style
 	Incorrect     lipgloss.Style
+	Missed        lipgloss.Style
 	Remaining     lipgloss.Style
 	Cursor        lipgloss.Style
+	ChartNet      lipgloss.Style
+	ChartRaw      lipgloss.Style
+	ChartError    lipgloss.Style
+	ChartAxis     lipgloss.Style
 }
 
 func DefaultStyles() Styles {
@@ -30,6 +38,7 @@ func DefaultStyles() Styles {
 		Subtitle:      lipgloss.NewStyle().Foreground(lipgloss.Color("244")),
 		QuoteBox:      lipgloss.NewStyle().MarginTop(1).BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("63")).Padding(1, 2),
 		QuoteContent:  lipgloss.NewStyle().Foreground(lipgloss.Color("252")),
+		Attribution:   lipgloss.NewStyle().Foreground(lipgloss.Color("250")).Italic(true),
 		Instruction:   lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Faint(true).Italic(true).MarginTop(1),
 		StatsRow:      lipgloss.NewStyle().MarginTop(1),
 		StatBlock:     lipgloss.NewStyle().Padding(0, 2, 0, 0),
@@ -37,9 +46,16 @@ func DefaultStyles() Styles {
 		StatValue:     lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Bold(true),
 		StatSeparator: separator,
 		Success:       lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Bold(true).MarginTop(1),
+		Failure:       lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true).MarginTop(1),
+		PersonalBest:  lipgloss.NewStyle().Foreground(lipgloss.Color("220")).Bold(true).MarginTop(1),
 		Typed:         lipgloss.NewStyle().Foreground(lipgloss.Color("42")),
 		Incorrect:     lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Underline(true),
+		Missed:        lipgloss.NewStyle().Foreground(lipgloss.Color("131")).Underline(true),
 		Remaining:     lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
 		Cursor:        lipgloss.NewStyle().Background(lipgloss.Color("218")).Foreground(lipgloss.Color("0")),
+		ChartNet:      lipgloss.NewStyle().Foreground(lipgloss.Color("212")),
+		ChartRaw:      lipgloss.NewStyle().Foreground(lipgloss.Color("60")),
+		ChartError:    lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true),
+		ChartAxis:     lipgloss.NewStyle().Foreground(lipgloss.Color("244")),
 	}
 }
@@ -46,15 +46,16 @@ const (
 	defaultTickInterval    = 100 * time.Millisecond
 )
 
-func InitialModel(languageWords models.LanguageWords, duration models.Duration, includePunctuation bool, includeNumbers bool) Model {
+func InitialModel(languageWords models.LanguageWords, duration models.Duration, includePunctuation bool, includeNumbers bool, sessionOptions typing.SessionOptions) Model {
 	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 	target := generateTargetWords(rng, languageWords, duration, includeNumbers, includePunctuation)
 	totalDuration := time.Duration(duration) * time.Second
 	if totalDuration <= 0 {
 		totalDuration = 60 * time.Second
 	}
+	sessionOptions.WordLevel = true
 	styles := theme.DefaultStyles()
-	session := typing.NewSession()
+	session := typing.NewSessionWithOptions(sessionOptions)
 
 	ti := textarea.New()
 	ti.Placeholder = target
@@ -128,9 +129,18 @@ func (m Model) Init() tea.Cmd {
 
 // Update handles messages (key presses, etc.)
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+	return m, tea.Batch(cmd, m.session.SaveCmd())
+}
+
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmds []tea.Cmd
+	prevValue := m.currentText.Value()
 
 	switch msg := msg.(type) {
+	case typing.SavedMsg:
+		m.session.Saved(msg)
+		return m, nil
 	case tea.WindowSizeMsg:
 		m.viewportWidth = msg.Width
 		metrics := typing.ComputeBoxMetrics(m.Target, m.styles, m.viewportWidth)
@@ -141,7 +151,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			remaining := m.deadline.Sub(msg.now)
 			if remaining <= 0 {
 				remaining = 0
-				m.session.Finish(msg.now, m.currentText.Value())
+				m.session.Finish(msg.now, m.currentText.Value(), m.Target)
 			}
 			m.remaining = remaining
 		}
@@ -179,10 +189,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case tea.KeyTab:
 			if !m.session.Finished() {
-				m.session.Finish(time.Now(), m.currentText.Value())
+				m.session.Abandon(time.Now(), m.currentText.Value(), m.Target)
 			}
 			return m, nil
 		}
+
+		if typing.IgnoreWordKey(msg, m.currentText.Value()) {
+			return m, nil
+		}
+		if m.session.Rejects(time.Now(), msg, m.currentText.Value(), m.Target) {
+			return m, nil
+		}
 	case error:
 		return m, nil
 	}
@@ -207,6 +224,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, tick)
 		}
 	}
+	m.session.Track(now, prevValue, m.currentText.Value(), m.Target)
 
 	m.ensureTargetBuffer()
 
@@ -229,6 +247,7 @@ func (m Model) View() string {
 			Session:       &m.session,
 			Metrics:       metrics,
 			ViewportWidth: m.viewportWidth,
+			WordLevel:     true,
 		}),
 		typing.RenderStats(typing.StatsConfig{
 			Target:        m.Target,
@@ -271,6 +290,9 @@ func (m Model) renderSubtitle(width int) string {
 	languageName := typing.DisplayLanguage(m.languageWords.Language)
 	chars := utf8.RuneCountInString(m.Target)
 	info := fmt.Sprintf("Language: %s · duration: %d · %d chars", languageName, m.duration, chars)
+	if best := typing.PersonalBestLabel(&m.session); best != "" {
+		info += " · " + best
+	}
 	return m.styles.Subtitle.MaxWidth(width).Render(info)
 }
 
@@ -0,0 +1,213 @@
+package typing
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/neilsmahajan/typing-test-tui/internal/ui/theme"
+)
+
+const DefaultChartHeight = 6
+
+type ChartConfig struct {
+	Width   int
+	Height  int
+	Styles  theme.Styles
+	Samples []Sample
+	Unit    string
+}
+
+// brailleBits maps a dot's (column, row) inside a braille cell to its bit.
+var brailleBits = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+type brailleGrid struct {
+	width  int
+	height int
+	cells  [][]rune
+}
+
+func newBrailleGrid(width, height int) *brailleGrid {
+	cells := make([][]rune, height)
+	for i := range cells {
+		cells[i] = make([]rune, width)
+	}
+	return &brailleGrid{width: width, height: height, cells: cells}
+}
+
+func (g *brailleGrid) set(x, y int) {
+	if x < 0 || y < 0 || x >= g.width*2 || y >= g.height*4 {
+		return
+	}
+	g.cells[y/4][x/2] |= brailleBits[x%2][y%4]
+}
+
+// line plots a straight segment between two dots using Bresenham's algorithm.
+func (g *brailleGrid) line(x0, y0, x1, y1 int) {
+	dx := int(math.Abs(float64(x1 - x0)))
+	dy := -int(math.Abs(float64(y1 - y0)))
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		g.set(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// RenderChart draws net and raw speed per second as braille line charts with
+// a row of markers under every second that contained an error.
+func RenderChart(cfg ChartConfig) string {
+	if len(cfg.Samples) < 2 {
+		return ""
+	}
+
+	height := cfg.Height
+	if height <= 0 {
+		height = DefaultChartHeight
+	}
+	unit := cfg.Unit
+	if unit == "" {
+		unit = "WPM"
+	}
+
+	maxValue := 0.0
+	for _, sample := range cfg.Samples {
+		maxValue = math.Max(maxValue, math.Max(sample.NetWPM, sample.RawWPM))
+	}
+	maxValue = math.Ceil(maxValue/10) * 10
+	if maxValue <= 0 {
+		maxValue = 10
+	}
+
+	topLabel := fmt.Sprintf("%.0f", maxValue)
+	labelWidth := len(topLabel)
+	plotWidth := cfg.Width - labelWidth - 1
+	if plotWidth < 2 {
+		return ""
+	}
+
+	dotsWide := plotWidth * 2
+	dotsHigh := height * 4
+	project := func(index int, value float64) (int, int) {
+		x := int(math.Round(float64(index) * float64(dotsWide-1) / float64(len(cfg.Samples)-1)))
+		y := dotsHigh - 1 - int(math.Round(value/maxValue*float64(dotsHigh-1)))
+		return x, y
+	}
+
+	net := newBrailleGrid(plotWidth, height)
+	raw := newBrailleGrid(plotWidth, height)
+	errorColumns := make([]bool, plotWidth)
+	for i, sample := range cfg.Samples {
+		x, y := project(i, sample.NetWPM)
+		rx, ry := project(i, sample.RawWPM)
+		if i == 0 {
+			net.set(x, y)
+			raw.set(rx, ry)
+		} else {
+			px, py := project(i-1, cfg.Samples[i-1].NetWPM)
+			prx, pry := project(i-1, cfg.Samples[i-1].RawWPM)
+			net.line(px, py, x, y)
+			raw.line(prx, pry, rx, ry)
+		}
+		if sample.Errors > 0 {
+			errorColumns[x/2] = true
+		}
+	}
+
+	axis := cfg.Styles.ChartAxis
+	lines := make([]string, 0, height+3)
+	for row := 0; row < height; row++ {
+		label := strings.Repeat(" ", labelWidth)
+		switch row {
+		case 0:
+			label = topLabel
+		case height - 1:
+			label = fmt.Sprintf("%*d", labelWidth, 0)
+		}
+		lines = append(lines, axis.Render(label+"│")+renderChartRow(cfg.Styles, net.cells[row], raw.cells[row]))
+	}
+
+	var markers strings.Builder
+	for _, marked := range errorColumns {
+		if marked {
+			markers.WriteString("×")
+		} else {
+			markers.WriteString(" ")
+		}
+	}
+	lines = append(lines, strings.Repeat(" ", labelWidth+1)+cfg.Styles.ChartError.Render(markers.String()))
+
+	first := "1s"
+	last := fmt.Sprintf("%ds", cfg.Samples[len(cfg.Samples)-1].Second)
+	gap := plotWidth - len(first) - len(last)
+	if gap < 1 {
+		gap = 1
+	}
+	lines = append(lines, axis.Render(strings.Repeat(" ", labelWidth+1)+first+strings.Repeat(" ", gap)+last))
+
+	legend := cfg.Styles.ChartNet.Render("━ "+strings.ToLower(unit)) + "  " +
+		cfg.Styles.ChartRaw.Render("━ raw") + "  " +
+		cfg.Styles.ChartError.Render("×") + axis.Render(" errors")
+	lines = append(lines, strings.Repeat(" ", labelWidth+1)+legend)
+
+	return lipgloss.NewStyle().MarginTop(1).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// renderChartRow merges the two series, drawing net over raw where they meet.
+func renderChartRow(styles theme.Styles, net, raw []rune) string {
+	var builder strings.Builder
+	var pending strings.Builder
+	current := -1
+	flush := func() {
+		if pending.Len() == 0 {
+			return
+		}
+		switch current {
+		case 1:
+			builder.WriteString(styles.ChartNet.Render(pending.String()))
+		case 2:
+			builder.WriteString(styles.ChartRaw.Render(pending.String()))
+		default:
+			builder.WriteString(pending.String())
+		}
+		pending.Reset()
+	}
+
+	for i := range net {
+		kind, glyph := 0, ' '
+		switch {
+		case net[i] != 0:
+			kind, glyph = 1, 0x2800+(net[i]|raw[i])
+		case raw[i] != 0:
+			kind, glyph = 2, 0x2800+raw[i]
+		}
+		if kind != current {
+			flush()
+			current = kind
+		}
+		pending.WriteRune(glyph)
+	}
+	flush()
+	return builder.String()
+}
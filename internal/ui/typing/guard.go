@@ -0,0 +1,170 @@
+package typing
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+// tabWidth is the number of spaces quote mode collapses into a tab.
+const tabWidth = 4
+
+// Rejects reports whether a key press must be dropped before it reaches the
+// text area, according to the session's stop-on-error and confidence
+// settings. While either is on, keys that move the cursor or edit text other
+// than at its end are dropped as well. typed is the text so far, compared
+// with target the same way Track compares it. Keys rejected for being wrong
+// are still logged as incorrect keystrokes, and fail a master difficulty
+// session.
+func (s *Session) Rejects(now time.Time, msg tea.KeyMsg, typed, target string) bool {
+	if s.finished {
+		return false
+	}
+	// The checks below judge keys at the end of typed, so the cursor has to
+	// stay there.
+	if s.guarded() && leavesEnd(msg) {
+		return true
+	}
+	if deletes(msg) {
+		return !s.mayDelete(msg, typed)
+	}
+	if s.options.Free || s.stopOnError() == models.StopOnErrorOff {
+		return false
+	}
+
+	typedRunes := []rune(typed)
+	targetRunes := []rune(target)
+	for _, r := range insertedRunes(msg) {
+		if s.accepts(typedRunes, r, targetRunes) {
+			typedRunes = append(typedRunes, r)
+			continue
+		}
+		s.reject(now, typedRunes, r, target)
+		return true
+	}
+	return false
+}
+
+// accepts reports whether r may be typed after typed under the stop-on-error
+// setting.
+func (s *Session) accepts(typed []rune, r rune, target []rune) bool {
+	if r == ' ' && spaceForTab(typed, target) {
+		return true
+	}
+	next := append(typed[:len(typed):len(typed)], r)
+	index := len(typed)
+
+	if s.options.WordLevel {
+		_, correct := wordKeystroke(next, index, string(target))
+		if s.stopOnError() == models.StopOnLetter {
+			return correct
+		}
+		// A space submits the word, so it is only wrong when the word is.
+		return r != ' ' || correct
+	}
+
+	if s.stopOnError() == models.StopOnLetter {
+		return index < len(target) && target[index] == r
+	}
+	return !submitsWrongWord(next, index, target)
+}
+
+// reject logs a dropped key as an incorrect keystroke at the position it was
+// meant for.
+func (s *Session) reject(now time.Time, typed []rune, r rune, target string) {
+	if !s.started {
+		return
+	}
+	position := len(typed)
+	if s.options.WordLevel {
+		position, _ = wordKeystroke(append(typed[:len(typed):len(typed)], r), len(typed), target)
+	}
+	s.keystrokes = append(s.keystrokes, Keystroke{Rune: r, Time: now, Position: position})
+	if s.options.Difficulty == models.MasterDifficulty {
+		s.finish(now, string(typed), target, models.OutcomeFailed)
+	}
+}
+
+// mayDelete reports whether the confidence setting allows a deleting key.
+func (s *Session) mayDelete(msg tea.KeyMsg, typed string) bool {
+	switch s.options.Confidence {
+	case models.ConfidenceMax:
+		return false
+	case models.ConfidenceOn:
+		// Deleting whitespace would reach back into a word already
+		// finished.
+		if msg.String() == "ctrl+u" {
+			line := typed[strings.LastIndex(typed, "\n")+1:]
+			return !strings.ContainsFunc(line, unicode.IsSpace)
+		}
+		runes := []rune(typed)
+		return len(runes) == 0 || !unicode.IsSpace(runes[len(runes)-1])
+	}
+	return true
+}
+
+// guarded reports whether the stop-on-error or confidence setting filters
+// keys.
+func (s *Session) guarded() bool {
+	switch s.options.Confidence {
+	case models.ConfidenceOn, models.ConfidenceMax:
+		return true
+	}
+	return !s.options.Free && s.stopOnError() != models.StopOnErrorOff
+}
+
+func (s *Session) stopOnError() models.StopOnError {
+	if s.options.StopOnError == "" {
+		return models.StopOnErrorOff
+	}
+	return s.options.StopOnError
+}
+
+// deletes reports whether a key removes text before the cursor.
+func deletes(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "backspace", "ctrl+h", "ctrl+w", "alt+backspace", "ctrl+u":
+		return true
+	}
+	return false
+}
+
+// leavesEnd reports whether a key moves the text area's cursor, or changes
+// text other than by typing or deleting before it.
+func leavesEnd(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "left", "right", "up", "down", "home", "end", "ctrl+home", "ctrl+end",
+		"ctrl+a", "ctrl+b", "ctrl+e", "ctrl+f", "ctrl+n", "ctrl+p",
+		"alt+left", "alt+right", "alt+b", "alt+f", "alt+<", "alt+>",
+		"delete", "ctrl+d", "ctrl+k", "alt+delete", "alt+d",
+		"ctrl+t", "alt+c", "alt+l", "alt+u":
+		return true
+	}
+	return false
+}
+
+// insertedRunes returns the text a key press types.
+func insertedRunes(msg tea.KeyMsg) []rune {
+	switch msg.Type {
+	case tea.KeyRunes:
+		return msg.Runes
+	case tea.KeySpace:
+		return []rune{' '}
+	case tea.KeyEnter:
+		return []rune{'\n'}
+	}
+	return nil
+}
+
+// spaceForTab reports whether another space may be typed towards a tab in
+// target, since quote mode accepts four spaces in place of one.
+func spaceForTab(typed, target []rune) bool {
+	start := len(typed)
+	for start > 0 && typed[start-1] == ' ' {
+		start--
+	}
+	return start < len(target) && target[start] == '\t' && len(typed)-start < tabWidth
+}
@@ -0,0 +1,167 @@
+package typing
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+func runeKey(r rune) tea.KeyMsg {
+	if r == ' ' {
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+func startedSession(opts SessionOptions) *Session {
+	session := NewSessionWithOptions(opts)
+	session.Start(time.Now())
+	return &session
+}
+
+func TestStopOnLetterRejectsWrongKeys(t *testing.T) {
+	session := startedSession(SessionOptions{StopOnError: models.StopOnLetter})
+	now := time.Now()
+
+	if session.Rejects(now, runeKey('l'), "he", "hello") {
+		t.Fatalf("expected the right key to be accepted")
+	}
+	if !session.Rejects(now, runeKey('x'), "he", "hello") {
+		t.Fatalf("expected a wrong key to be rejected")
+	}
+	if !session.Rejects(now, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("lx")}, "he", "hello") {
+		t.Fatalf("expected a paste with a wrong key to be rejected")
+	}
+	keystrokes := session.Keystrokes()
+	if len(keystrokes) != 2 || keystrokes[0].Correct || keystrokes[0].Position != 2 || keystrokes[1].Position != 3 {
+		t.Fatalf("expected rejected keys to be logged as mistakes, got %+v", keystrokes)
+	}
+	if session.Finished() {
+		t.Fatalf("expected rejected keys not to end a normal session")
+	}
+}
+
+func TestStopOnLetterFailsMaster(t *testing.T) {
+	session := startedSession(SessionOptions{StopOnError: models.StopOnLetter, Difficulty: models.MasterDifficulty})
+	if !session.Rejects(time.Now(), runeKey('x'), "he", "hello") || session.Outcome() != models.OutcomeFailed {
+		t.Fatalf("expected a rejected key to fail a master session, got %q", session.Outcome())
+	}
+}
+
+func TestStopOnWordBlocksSubmittingMistakes(t *testing.T) {
+	session := startedSession(SessionOptions{StopOnError: models.StopOnWord})
+	now := time.Now()
+
+	if session.Rejects(now, runeKey('x'), "hel", "hello world") {
+		t.Fatalf("expected wrong letters to be accepted")
+	}
+	if !session.Rejects(now, runeKey(' '), "helxo", "hello world") {
+		t.Fatalf("expected a space after a wrong word to be rejected")
+	}
+	if session.Rejects(now, tea.KeyMsg{Type: tea.KeyEnter}, "hel", "hel\nlo") {
+		t.Fatalf("expected Enter after a correct word to be accepted")
+	}
+	if session.Rejects(now, runeKey(' '), "hello", "hello world") {
+		t.Fatalf("expected a space after a correct word to be accepted")
+	}
+}
+
+func TestStopOnErrorWordLevel(t *testing.T) {
+	letter := startedSession(SessionOptions{StopOnError: models.StopOnLetter, WordLevel: true})
+	now := time.Now()
+	if letter.Rejects(now, runeKey('w'), "hello ", "hello world") {
+		t.Fatalf("expected the first letter of the next word to be accepted")
+	}
+	if !letter.Rejects(now, runeKey('s'), "hello", "hello world") {
+		t.Fatalf("expected an extra letter to be rejected")
+	}
+	if !letter.Rejects(now, runeKey(' '), "hel", "hello world") {
+		t.Fatalf("expected submitting a partial word to be rejected")
+	}
+
+	word := startedSession(SessionOptions{StopOnError: models.StopOnWord, WordLevel: true})
+	if word.Rejects(now, runeKey('s'), "hello", "hello world") {
+		t.Fatalf("expected an extra letter to be accepted")
+	}
+	if !word.Rejects(now, runeKey(' '), "hellos", "hello world") {
+		t.Fatalf("expected submitting a wrong word to be rejected")
+	}
+}
+
+func TestStopOnErrorAllowsSpacesForTabs(t *testing.T) {
+	session := startedSession(SessionOptions{StopOnError: models.StopOnLetter})
+	now := time.Now()
+	for _, typed := range []string{"if {\n", "if {\n ", "if {\n   "} {
+		if session.Rejects(now, runeKey(' '), typed, "if {\n\tx\n}") {
+			t.Fatalf("expected a space towards a tab after %q to be accepted", typed)
+		}
+	}
+	if !session.Rejects(now, runeKey(' '), "if {\n\t", "if {\n\tx\n}") {
+		t.Fatalf("expected a space once the tab is complete to be rejected")
+	}
+}
+
+func TestConfidenceLimitsBackspace(t *testing.T) {
+	now := time.Now()
+	backspace := tea.KeyMsg{Type: tea.KeyBackspace}
+
+	on := startedSession(SessionOptions{Confidence: models.ConfidenceOn})
+	if on.Rejects(now, backspace, "hello wo", "hello world") {
+		t.Fatalf("expected backspace within the current word to be allowed")
+	}
+	if !on.Rejects(now, backspace, "hello ", "hello world") {
+		t.Fatalf("expected backspace into the previous word to be rejected")
+	}
+	if !on.Rejects(now, tea.KeyMsg{Type: tea.KeyCtrlU}, "hello wo", "hello world") {
+		t.Fatalf("expected deleting the line across words to be rejected")
+	}
+
+	strict := startedSession(SessionOptions{Confidence: models.ConfidenceMax, Free: true})
+	if !strict.Rejects(now, backspace, "hello wo", "") || !strict.Rejects(now, tea.KeyMsg{Type: tea.KeyCtrlW}, "hello wo", "") {
+		t.Fatalf("expected every deletion to be rejected")
+	}
+	if strict.Rejects(now, runeKey('x'), "hello wo", "") {
+		t.Fatalf("expected typing to be unaffected")
+	}
+	if len(strict.Keystrokes()) != 0 {
+		t.Fatalf("expected rejected deletions not to be logged")
+	}
+
+	off := startedSession(SessionOptions{})
+	if off.Rejects(now, backspace, "hello ", "hello world") || off.Rejects(now, runeKey('x'), "h", "hello") {
+		t.Fatalf("expected every key to be accepted by default")
+	}
+}
+
+func TestGuardsKeepTheCursorAtTheEnd(t *testing.T) {
+	now := time.Now()
+	left := tea.KeyMsg{Type: tea.KeyLeft}
+	forward := []tea.KeyMsg{{Type: tea.KeyDelete}, {Type: tea.KeyCtrlD}, {Type: tea.KeyCtrlK}}
+
+	for _, opts := range []SessionOptions{
+		{Confidence: models.ConfidenceOn},
+		{Confidence: models.ConfidenceMax},
+		{StopOnError: models.StopOnLetter},
+	} {
+		session := startedSession(opts)
+		if !session.Rejects(now, left, "hello wo", "hello world") {
+			t.Fatalf("%+v: expected moving the cursor back to be rejected", opts)
+		}
+		for _, key := range forward {
+			if !session.Rejects(now, key, "hello wo", "hello world") {
+				t.Fatalf("%+v: expected %q to be rejected", opts, key)
+			}
+		}
+	}
+
+	off := startedSession(SessionOptions{})
+	if off.Rejects(now, left, "hello wo", "hello world") || off.Rejects(now, forward[0], "hello wo", "hello world") {
+		t.Fatalf("expected cursor keys to be accepted by default")
+	}
+	zen := startedSession(SessionOptions{StopOnError: models.StopOnLetter, Free: true})
+	if zen.Rejects(now, left, "hello wo", "") {
+		t.Fatalf("expected stop-on-error not to hold the cursor in a free session")
+	}
+}
@@ -0,0 +1,64 @@
+package typing
+
+import "github.com/neilsmahajan/typing-test-tui/internal/models"
+
+// Recorder persists finished sessions.
+type Recorder interface {
+	Append(result models.Result) error
+}
+
+// SessionOptions controls how a Session scores the text typed into it.
+type SessionOptions struct {
+	Metric  models.WPMMetric
+	Scoring models.Scoring
+	// WordLevel aligns typed words with target words (see EvaluateWords)
+	// instead of comparing the two texts character by character.
+	WordLevel bool
+	// Free sessions have no target text: everything typed counts as correct,
+	// as in zen mode.
+	Free bool
+	// Difficulty fails sessions on mistakes at the expert and master levels.
+	Difficulty models.Difficulty
+	// StopOnError and Confidence decide which keys Rejects drops.
+	StopOnError models.StopOnError
+	Confidence  models.Confidence
+	// Config describes the test in stored results.
+	Config models.Config
+	// Recorder, when set, receives every finished session.
+	Recorder Recorder
+	// PersonalBest is the best speed recorded for Config before this run.
+	// Completed sessions that beat it raise it for the following tests.
+	PersonalBest float64
+}
+
+// SessionOptionsFromConfig derives the session options for a test configuration.
+func SessionOptionsFromConfig(cfg models.Config) SessionOptions {
+	return SessionOptions{
+		Metric:      cfg.WPMMetric,
+		Scoring:     cfg.Language.Scoring(),
+		Free:        cfg.Mode == models.ZenMode,
+		Difficulty:  cfg.Difficulty,
+		StopOnError: cfg.StopOnError,
+		Confidence:  cfg.Confidence,
+		Config:      cfg,
+	}
+}
+
+func (o SessionOptions) charsPerWord() float64 {
+	if o.Scoring.CharsPerWord <= 0 {
+		return averageWordLength
+	}
+	return o.Scoring.CharsPerWord
+}
+
+func (o SessionOptions) unit() string {
+	if o.Scoring.Unit == "" {
+		return "WPM"
+	}
+	return o.Scoring.Unit
+}
+
+// segmented defaults to true so a zero Scoring behaves like English.
+func (o SessionOptions) segmented() bool {
+	return o.Scoring == (models.Scoring{}) || o.Scoring.Segmented
+}
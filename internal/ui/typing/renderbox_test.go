@@ -81,7 +81,7 @@ func TestRenderBoxDisplaysNewlineIndicator(t *testing.T) {
 	metrics := ComputeBoxMetrics(target, styles, 0)
 	session := NewSession()
 	session.Start(time.Now().Add(-time.Second))
-	session.Finish(time.Now(), target)
+	session.Finish(time.Now(), typed, target)
 
 	output := RenderBox(BoxConfig{
 		Target:           target,
@@ -141,3 +141,39 @@ func sanitizeANSI(input string) string {
 	}
 	return b.String()
 }
+
+func TestRenderBoxMarksMultibyteMistakes(t *testing.T) {
+	styles := theme.DefaultStyles()
+	styles.Incorrect = styles.Incorrect.Transform(func(s string) string { return "[" + s + "]" })
+	session := NewSession()
+
+	cases := []struct {
+		target   string
+		typed    string
+		expected string
+	}{
+		{target: "café au lait", typed: "cafe", expected: "caf[é]"},
+		{target: "niño", typed: "nin", expected: "ni[ñ]o"},
+		{target: "我们他们", typed: "我门", expected: "我[们]他们"},
+	}
+
+	for _, tc := range cases {
+		output := RenderBox(BoxConfig{
+			Target:  tc.target,
+			Typed:   tc.typed,
+			Styles:  styles,
+			Session: &session,
+			Metrics: ComputeBoxMetrics(tc.target, styles, 40),
+		})
+		if cleaned := sanitizeANSI(output); !strings.Contains(cleaned, tc.expected) {
+			t.Fatalf("expected %q in rendered output for typed %q, got:\n%s", tc.expected, tc.typed, cleaned)
+		}
+	}
+}
+
+func TestSplitGraphemesKeepsCombiningMarks(t *testing.T) {
+	clusters := splitGraphemes("éa")
+	if len(clusters) != 2 || clusters[0] != "é" {
+		t.Fatalf("expected combining sequence to stay together, got %q", clusters)
+	}
+}
@@ -0,0 +1,159 @@
+package typing
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+// CharCounts classifies every character position of a finished test.
+// Correct and Incorrect cover typed characters that overlap the target,
+// Extra counts characters typed past the end of the target and Missed
+// counts target characters that were never typed.
+type CharCounts struct {
+	Correct   int
+	Incorrect int
+	Extra     int
+	Missed    int
+}
+
+// Typed returns the number of characters that ended up in the input.
+func (c CharCounts) Typed() int {
+	return c.Correct + c.Incorrect + c.Extra
+}
+
+// Errors returns the number of uncorrected typed errors.
+func (c CharCounts) Errors() int {
+	return c.Incorrect + c.Extra
+}
+
+// Results summarises a finished session.
+type Results struct {
+	WPM      float64
+	RawWPM   float64
+	NetWPM   float64
+	CPM      float64
+	Accuracy float64
+	Chars    CharCounts
+	// Words is only populated for word-level sessions.
+	Words WordCounts
+	// Timeline holds one sample per elapsed second.
+	Timeline []Sample
+	// Consistency is 0-100, higher when per-second raw speed varies less.
+	Consistency float64
+}
+
+func computeResults(elapsed time.Duration, typed, target string, keystrokes []Keystroke, opts SessionOptions) Results {
+	results := Results{
+		Chars:    countChars(typed, target),
+		Accuracy: accuracy(typed, target, keystrokes),
+	}
+	if opts.WordLevel {
+		words := EvaluateWords(typed, target)
+		finalizeWords(words)
+		results.Chars = countWordChars(words)
+		results.Words = countWords(words)
+	}
+
+	minutes := elapsed.Minutes()
+	if minutes <= 0 {
+		return results
+	}
+
+	results.WPM = speed(elapsed, typed, results.Chars, opts)
+	results.RawWPM = sanitize(float64(results.Chars.Typed()) / opts.charsPerWord() / minutes)
+	results.NetWPM = sanitize(math.Max(0, results.RawWPM-float64(results.Chars.Errors())/minutes))
+	results.CPM = sanitize(float64(results.Chars.Correct) / minutes)
+	return results
+}
+
+// speed scores typed according to the configured metric. The default counts
+// correctly typed characters in units of the language's chars-per-word; the
+// legacy word metric counts whitespace separated words regardless of
+// correctness, falling back to characters for unsegmented scripts.
+func speed(elapsed time.Duration, typed string, chars CharCounts, opts SessionOptions) float64 {
+	minutes := elapsed.Minutes()
+	if minutes <= 0 {
+		return 0
+	}
+	if opts.Metric == models.WordWPM {
+		return sanitize(legacyWords(typed, opts) / minutes)
+	}
+	return sanitize(float64(chars.Correct) / opts.charsPerWord() / minutes)
+}
+
+// liveChars classifies typed against target while a session is running, word
+// by word for word-level sessions as computeResults does, so one skipped
+// letter does not misalign everything typed after it.
+func liveChars(typed, target string, opts SessionOptions) CharCounts {
+	if opts.WordLevel {
+		return countWordChars(EvaluateWords(typed, target))
+	}
+	return countChars(typed, target)
+}
+
+func countChars(typed, target string) CharCounts {
+	typedRunes := []rune(typed)
+	targetRunes := []rune(target)
+
+	var counts CharCounts
+	for i, r := range typedRunes {
+		switch {
+		case i >= len(targetRunes):
+			counts.Extra++
+		case targetRunes[i] == r:
+			counts.Correct++
+		default:
+			counts.Incorrect++
+		}
+	}
+	if len(targetRunes) > len(typedRunes) {
+		counts.Missed = len(targetRunes) - len(typedRunes)
+	}
+	return counts
+}
+
+// accuracy prefers the keystroke log so corrected mistakes still count
+// against the score, falling back to the final text when nothing was logged.
+func accuracy(typed, target string, keystrokes []Keystroke) float64 {
+	correct, total := 0, 0
+	for _, k := range keystrokes {
+		if k.Backspace {
+			continue
+		}
+		total++
+		if k.Correct {
+			correct++
+		}
+	}
+
+	if total == 0 {
+		counts := countChars(typed, target)
+		correct, total = counts.Correct, counts.Typed()
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(correct) / float64(total) * 100
+}
+
+func legacyWords(text string, opts SessionOptions) float64 {
+	runes := float64(len([]rune(strings.Join(strings.Fields(text), ""))))
+	if !opts.segmented() {
+		return runes / opts.charsPerWord()
+	}
+	words := float64(WordCount(text))
+	if words <= 0 {
+		words = runes / opts.charsPerWord()
+	}
+	return words
+}
+
+func sanitize(value float64) float64 {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0
+	}
+	return value
+}
@@ -0,0 +1,54 @@
+package typing
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+)
+
+func TestCountChars(t *testing.T) {
+	counts := countChars("hxllo!!", "hello")
+	expected := CharCounts{Correct: 4, Incorrect: 1, Extra: 2}
+	if counts != expected {
+		t.Fatalf("expected %+v, got %+v", expected, counts)
+	}
+	if counts.Errors() != 3 || counts.Typed() != 7 {
+		t.Fatalf("unexpected derived counts: errors=%d typed=%d", counts.Errors(), counts.Typed())
+	}
+}
+
+func TestComputeResultsNetWPMPenalisesErrors(t *testing.T) {
+	results := computeResults(time.Minute, "hellx world", "hello world", nil, SessionOptions{})
+	if math.Abs(results.RawWPM-2.2) > 0.0001 {
+		t.Fatalf("expected raw WPM of 2.2, got %f", results.RawWPM)
+	}
+	if math.Abs(results.NetWPM-1.2) > 0.0001 {
+		t.Fatalf("expected net WPM of 1.2, got %f", results.NetWPM)
+	}
+	if math.Abs(results.Accuracy-float64(10)/11*100) > 0.0001 {
+		t.Fatalf("expected accuracy from final text without keystrokes, got %f", results.Accuracy)
+	}
+}
+
+func TestComputeResultsZeroElapsed(t *testing.T) {
+	results := computeResults(0, "abc", "abc", nil, SessionOptions{})
+	if results.WPM != 0 || results.RawWPM != 0 || results.CPM != 0 {
+		t.Fatalf("expected zero speeds for zero elapsed time, got %+v", results)
+	}
+}
+
+func TestComputeResultsUnsegmentedLanguage(t *testing.T) {
+	opts := SessionOptionsFromConfig(models.Config{Language: models.Chinese})
+	results := computeResults(time.Minute, "我们他们", "我们他们", nil, opts)
+	if math.Abs(results.WPM-4) > 0.0001 {
+		t.Fatalf("expected 4 characters per minute, got %f", results.WPM)
+	}
+
+	opts.Metric = models.WordWPM
+	results = computeResults(time.Minute, "我们他们", "我们他们", nil, opts)
+	if math.Abs(results.WPM-4) > 0.0001 {
+		t.Fatalf("expected word metric to fall back to characters for Chinese, got %f", results.WPM)
+	}
+}
@@ -1,10 +1,13 @@
 package typing
 
 import (
-	"math"
-	"strings"
+	"errors"
+	"sync"
 	"time"
-	"unicode/utf8"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
 )
 
 const (
@@ -15,23 +18,60 @@ const (
 )
 
 type Session struct {
-	started  bool
-	start    time.Time
-	finished bool
-	end      time.Time
-	wpm      float64
+	started    bool
+	start      time.Time
+	finished   bool
+	end        time.Time
+	wpm        float64
+	results    Results
+	keystrokes []Keystroke
+	options    SessionOptions
+	outcome    models.Outcome
+	quoteID    int
+	recordErr  error
+	newBest    bool
+	prevBest   float64
+	// unsaved is set when a finished result still has to be handed to the
+	// Recorder, and run tells apart the tests a reused session has held.
+	unsaved bool
+	run     int
+}
+
+// Keystroke is a single input event recorded while a session is running.
+// Position is the rune index in the target that the keystroke applied to.
+type Keystroke struct {
+	Rune      rune
+	Time      time.Time
+	Position  int
+	Correct   bool
+	Backspace bool
 }
 
 func NewSession() Session {
 	return Session{}
 }
 
+// NewSessionWithOptions returns a session that scores according to opts.
+// Options survive Reset so a model can reuse its session between tests.
+func NewSessionWithOptions(opts SessionOptions) Session {
+	return Session{options: opts}
+}
+
 func (s *Session) Reset() {
 	s.started = false
 	s.finished = false
 	s.start = time.Time{}
 	s.end = time.Time{}
 	s.wpm = 0
+	s.results = Results{}
+	s.keystrokes = nil
+	s.outcome = ""
+	s.quoteID = 0
+	s.recordErr = nil
+	s.newBest = false
+	s.prevBest = 0
+	s.unsaved = false
+	s.run++
 }
 
 func (s *Session) Start(now time.Time) {
@@ -42,58 +82,262 @@ func (s *Session) Start(now time.Time) {
 	s.start = now
 }
 
-func (s *Session) Finish(now time.Time, target string) float64 {
+// Track records the keystrokes that turned previous into current. Runes that
+// disappeared are logged as backspaces and new runes are checked against
+// target at the position they were typed. When a single update both removes
+// and adds runes (e.g. four spaces collapsing into a tab) the rewritten runes
+// are treated as replaced rather than deleted.
+func (s *Session) Track(now time.Time, previous, current, target string) {
+	if !s.started || s.finished || previous == current {
+		return
+	}
+	if s.options.Free {
+		target = current
+	}
+
+	prevRunes := []rune(previous)
+	currRunes := []rune(current)
+	targetRunes := []rune(target)
+
+	prefix := 0
+	for prefix < len(prevRunes) && prefix < len(currRunes) && prevRunes[prefix] == currRunes[prefix] {
+		prefix++
+	}
+
+	removed := prevRunes[prefix:]
+	added := currRunes[prefix:]
+
+	if len(removed) > 0 && len(added) > 0 {
+		s.retract(prefix, len(removed))
+	} else {
+		for i := len(removed) - 1; i >= 0; i-- {
+			s.keystrokes = append(s.keystrokes, Keystroke{
+				Rune:      removed[i],
+				Time:      now,
+				Position:  prefix + i,
+				Backspace: true,
+			})
+		}
+	}
+
+	for i, r := range added {
+		position := prefix + i
+		correct := position < len(targetRunes) && targetRunes[position] == r
+		if s.options.WordLevel {
+			position, correct = wordKeystroke(currRunes, prefix+i, target)
+		}
+		s.keystrokes = append(s.keystrokes, Keystroke{
+			Rune:     r,
+			Time:     now,
+			Position: position,
+			Correct:  correct,
+		})
+		if s.breaksDifficulty(currRunes, prefix+i, targetRunes, correct) {
+			s.finish(now, current, target, models.OutcomeFailed)
+			return
+		}
+	}
+}
+
+// breaksDifficulty reports whether typing the rune at index fails the session
+// under its difficulty. correct is the verdict already given to the keystroke.
+func (s *Session) breaksDifficulty(typed []rune, index int, target []rune, correct bool) bool {
+	if s.options.Free {
+		return false
+	}
+	// Spaces typed towards a tab are not mistakes until they fail to add up
+	// to one (see spaceForTab).
+	if typed[index] == ' ' && spaceForTab(typed[:index], target) {
+		return false
+	}
+	switch s.options.Difficulty {
+	case models.MasterDifficulty:
+		return !correct
+	case models.ExpertDifficulty:
+		if s.options.WordLevel {
+			// Word-level keystrokes judge a space by the word it submits.
+			return typed[index] == ' ' && !correct
+		}
+		return submitsWrongWord(typed, index, target)
+	}
+	return false
+}
+
+// submitsWrongWord reports whether the rune at index is whitespace that ends
+// a word differing from the target at the same position.
+func submitsWrongWord(typed []rune, index int, target []rune) bool {
+	if !unicode.IsSpace(typed[index]) || index == 0 || unicode.IsSpace(typed[index-1]) {
+		return false
+	}
+	start := index - 1
+	for start > 0 && !unicode.IsSpace(typed[start-1]) {
+		start--
+	}
+	return index >= len(target) || string(typed[start:index+1]) != string(target[start:index+1])
+}
+
+// retract drops up to count trailing keystrokes at or after position.
+func (s *Session) retract(position, count int) {
+	for count > 0 && len(s.keystrokes) > 0 {
+		last := s.keystrokes[len(s.keystrokes)-1]
+		if last.Backspace || last.Position < position {
+			return
+		}
+		s.keystrokes = s.keystrokes[:len(s.keystrokes)-1]
+		count--
+	}
+}
+
+// Finish ends a session that ran to completion and scores typed against
+// target. When a Recorder is configured the result is persisted by the
+// command SaveCmd returns next.
+func (s *Session) Finish(now time.Time, typed, target string) float64 {
+	return s.finish(now, typed, target, models.OutcomeCompleted)
+}
+
+// Abandon ends the session early, e.g. when the user presses Tab. It is
+// scored and recorded like Finish but marked as incomplete.
+func (s *Session) Abandon(now time.Time, typed, target string) float64 {
+	return s.finish(now, typed, target, models.OutcomeIncomplete)
+}
+
+func (s *Session) finish(now time.Time, typed, target string, outcome models.Outcome) float64 {
 	if s.finished {
 		return s.wpm
 	}
 	s.finished = true
 	s.end = now
-	elapsedMinutes := s.Elapsed(now).Minutes()
-	if elapsedMinutes <= 0 {
-		s.wpm = 0
-		return s.wpm
+	s.outcome = outcome
+	if s.options.Free {
+		target = typed
+	}
+	s.results = computeResults(s.Elapsed(now), typed, target, s.keystrokes, s.options)
+	s.results.Timeline = buildTimeline(s.start, s.Elapsed(now), s.keystrokes, s.options)
+	s.results.Consistency = consistency(s.results.Timeline)
+	s.wpm = s.results.WPM
+	s.updatePersonalBest()
+
+	s.unsaved = s.started && s.options.Recorder != nil
+	return s.wpm
+}
+
+// SavedMsg reports that SaveCmd finished writing a session's result.
+type SavedMsg struct {
+	run int
+	Err error
+}
+
+// pendingSave is a finished result waiting to be written. It is written at
+// most once, by whichever of its command and FlushSaves gets there first.
+type pendingSave struct {
+	once     sync.Once
+	recorder Recorder
+	result   models.Result
+	err      error
+}
+
+// write appends the result unless that already happened, and reports whether
+// this call wrote it.
+func (p *pendingSave) write() (bool, error) {
+	wrote := false
+	p.once.Do(func() {
+		wrote = true
+		p.err = p.recorder.Append(p.result)
+	})
+	return wrote, p.err
+}
+
+// pending holds the results handed out by SaveCmd that are not written yet.
+// A program that quits right after a test can drop the command, so
+// FlushSaves writes whatever is left.
+var pending struct {
+	sync.Mutex
+	saves []*pendingSave
+}
+
+// SaveCmd returns a command that hands the finished result to the Recorder,
+// or nil when there is nothing to save. Writing can wait on other instances
+// holding the history lock, so it runs outside of Update. Models pass the
+// resulting SavedMsg back to Saved.
+func (s *Session) SaveCmd() tea.Cmd {
+	if !s.unsaved {
+		return nil
 	}
-	words := float64(len(strings.Fields(target)))
-	if words <= 0 {
-		runes := utf8.RuneCountInString(target)
-		if runes > 0 {
-			words = float64(runes) / averageWordLength
+	s.unsaved = false
+	save := &pendingSave{recorder: s.options.Recorder, result: s.Result()}
+	pending.Lock()
+	pending.saves = append(pending.saves, save)
+	pending.Unlock()
+
+	run := s.run
+	return func() tea.Msg {
+		_, err := save.write()
+		forget(save)
+		return SavedMsg{run: run, Err: err}
+	}
+}
+
+func forget(save *pendingSave) {
+	pending.Lock()
+	defer pending.Unlock()
+	for i, p := range pending.saves {
+		if p == save {
+			pending.saves = append(pending.saves[:i], pending.saves[i+1:]...)
+			return
 		}
 	}
-	if words <= 0 {
-		s.wpm = 0
-		return s.wpm
+}
+
+// Saved records the outcome of SaveCmd, unless the session has moved on to
+// another test since.
+func (s *Session) Saved(msg SavedMsg) {
+	if msg.run == s.run {
+		s.recordErr = msg.Err
 	}
-	s.wpm = words / elapsedMinutes
-	if math.IsNaN(s.wpm) || math.IsInf(s.wpm, 0) {
-		s.wpm = 0
+}
+
+// FlushSaves writes every result whose SaveCmd has not finished, waiting for
+// those already being written. Call it after a program exits so a quick
+// Ctrl+C cannot lose a result. It returns the errors of the writes it made
+// itself, since the program could not show them.
+func FlushSaves() error {
+	pending.Lock()
+	saves := pending.saves
+	pending.saves = nil
+	pending.Unlock()
+
+	var errs []error
+	for _, save := range saves {
+		if wrote, err := save.write(); wrote && err != nil {
+			errs = append(errs, err)
+		}
 	}
-	return s.wpm
+	return errors.Join(errs...)
 }
 
-func (s *Session) CurrentWPM(now time.Time, typed string) float64 {
+// updatePersonalBest raises the personal best when a completed session beats
+// it. The very first result for a configuration sets the best silently since
+// there was nothing to beat.
+func (s *Session) updatePersonalBest() {
+	if !s.started || s.outcome != models.OutcomeCompleted || s.wpm <= s.options.PersonalBest {
+		return
+	}
+	s.prevBest = s.options.PersonalBest
+	s.newBest = s.prevBest > 0
+	s.options.PersonalBest = s.wpm
+}
+
+func (s *Session) CurrentWPM(now time.Time, typed, target string) float64 {
 	if s.finished {
 		return s.wpm
 	}
 	if !s.started {
 		return 0
 	}
-	elapsed := now.Sub(s.start).Minutes()
-	if elapsed <= 0 {
-		return 0
-	}
-	words := float64(len(strings.Fields(typed)))
-	if words == 0 {
-		words = float64(utf8.RuneCountInString(typed)) / averageWordLength
+	if s.options.Free {
+		target = typed
 	}
-	if words <= 0 {
-		return 0
-	}
-	value := words / elapsed
-	if math.IsNaN(value) || math.IsInf(value, 0) {
-		return 0
-	}
-	return value
+	return speed(now.Sub(s.start), typed, liveChars(typed, target, s.options), s.options)
 }
 
 func (s *Session) Elapsed(now time.Time) time.Duration {
@@ -114,6 +358,52 @@ func (s *Session) Finished() bool {
 	return s.finished
 }
 
+// Free reports whether the session has no target text (see
+// SessionOptions.Free).
+func (s *Session) Free() bool {
+	return s.options.Free
+}
+
+// KeystrokeCount returns the number of keys pressed, including backspaces.
+func (s *Session) KeystrokeCount() int {
+	return len(s.keystrokes)
+}
+
+// Outcome reports how a finished session ended.
+func (s *Session) Outcome() models.Outcome {
+	return s.outcome
+}
+
+// Difficulty returns the difficulty the session is typed at.
+func (s *Session) Difficulty() models.Difficulty {
+	if s.options.Difficulty == "" {
+		return models.NormalDifficulty
+	}
+	return s.options.Difficulty
+}
+
+// SetQuoteID tags the current test with the quote being typed.
+func (s *Session) SetQuoteID(id int) {
+	s.quoteID = id
+}
+
+// PersonalBest returns the best speed for the session's configuration,
+// including the current result once it has finished.
+func (s *Session) PersonalBest() float64 {
+	return s.options.PersonalBest
+}
+
+// NewPersonalBest reports whether the finished session beat the previous
+// personal best, and what that best was.
+func (s *Session) NewPersonalBest() (float64, bool) {
+	return s.prevBest, s.newBest
+}
+
+// RecordError returns the error from persisting the last result, if any.
+func (s *Session) RecordError() error {
+	return s.recordErr
+}
+
 func (s *Session) StartTime() time.Time {
 	return s.start
 }
@@ -122,6 +412,71 @@ func (s *Session) EndTime() time.Time {
 	return s.end
 }
 
+// Metric returns the WPM metric the session reports.
+func (s *Session) Metric() models.WPMMetric {
+	if s.options.Metric == "" {
+		return models.CharacterWPM
+	}
+	return s.options.Metric
+}
+
+// Unit returns the label for the session's speed, e.g. "WPM" or "CPM".
+func (s *Session) Unit() string {
+	return s.options.unit()
+}
+
 func (s *Session) WPM() float64 {
 	return s.wpm
 }
+
+// Results returns the scores captured when the session finished.
+func (s *Session) Results() Results {
+	return s.results
+}
+
+// Keystrokes returns a copy of the recorded keystroke log.
+func (s *Session) Keystrokes() []Keystroke {
+	result := make([]Keystroke, len(s.keystrokes))
+	copy(result, s.keystrokes)
+	return result
+}
+
+// Result describes the finished session together with the test
+// configuration it ran under, ready to be stored.
+func (s *Session) Result() models.Result {
+	cfg := s.options.Config
+	result := models.Result{
+		Mode:           cfg.Mode,
+		Language:       cfg.Language,
+		Punctuation:    cfg.IncludePunctuation,
+		Numbers:        cfg.IncludeNumbers,
+		QuoteID:        s.quoteID,
+		Outcome:        s.outcome,
+		Metric:         s.Metric(),
+		Unit:           s.Unit(),
+		WPM:            s.results.WPM,
+		RawWPM:         s.results.RawWPM,
+		NetWPM:         s.results.NetWPM,
+		CPM:            s.results.CPM,
+		Accuracy:       s.results.Accuracy,
+		Consistency:    s.results.Consistency,
+		CorrectChars:   s.results.Chars.Correct,
+		IncorrectChars: s.results.Chars.Incorrect,
+		ExtraChars:     s.results.Chars.Extra,
+		MissedChars:    s.results.Chars.Missed,
+		StartedAt:      s.start,
+		FinishedAt:     s.end,
+		ElapsedSeconds: s.Elapsed(s.end).Seconds(),
+	}
+	if difficulty := s.Difficulty(); difficulty != models.NormalDifficulty {
+		result.Difficulty = difficulty
+	}
+	// Duration and word count only describe the modes that use them.
+	switch cfg.Mode {
+	case models.TimeMode:
+		result.Duration = cfg.Duration
+	case models.WordsMode:
+		result.WordCount = cfg.WordCount
+	}
+	return result
+}
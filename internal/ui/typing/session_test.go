@@ -1,9 +1,13 @@
 package typing
 
 import (
+	"errors"
 	"math"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
 )
 
 func TestSessionLifecycle(t *testing.T) {
@@ -19,15 +23,15 @@ func TestSessionLifecycle(t *testing.T) {
 	}
 
 	halfway := start.Add(30 * time.Second)
-	wpm := session.CurrentWPM(halfway, "hello world")
+	wpm := session.CurrentWPM(halfway, "hello world", "hello world")
 	if wpm <= 0 {
 		t.Fatalf("expected positive WPM, got %f", wpm)
 	}
 
 	finish := start.Add(time.Minute)
-	final := session.Finish(finish, "hello world")
-	if math.Abs(final-2) > 0.0001 {
-		t.Fatalf("expected finish WPM to be close to 2, got %f", final)
+	final := session.Finish(finish, "hello world", "hello world")
+	if math.Abs(final-2.2) > 0.0001 {
+		t.Fatalf("expected finish WPM to be close to 2.2, got %f", final)
 	}
 	if !session.Finished() {
 		t.Fatalf("expected session to be finished")
@@ -41,7 +45,7 @@ func TestSessionReset(t *testing.T) {
 	session := NewSession()
 	now := time.Now()
 	session.Start(now)
-	session.Finish(now.Add(time.Second), "test")
+	session.Finish(now.Add(time.Second), "test", "test")
 
 	session.Reset()
 	if session.Started() || session.Finished() {
@@ -59,8 +63,442 @@ func TestSessionFinishZeroDuration(t *testing.T) {
 	session := NewSession()
 	now := time.Now()
 	session.Start(now)
-	wpm := session.Finish(now, "word")
+	wpm := session.Finish(now, "word", "word")
 	if wpm != 0 {
 		t.Fatalf("expected zero wpm for zero duration finish, got %f", wpm)
 	}
 }
+
+func TestSessionTrackRecordsKeystrokes(t *testing.T) {
+	session := NewSession()
+	now := time.Now()
+	session.Start(now)
+
+	session.Track(now, "", "h", "hi")
+	session.Track(now.Add(time.Second), "h", "hx", "hi")
+	session.Track(now.Add(2*time.Second), "hx", "h", "hi")
+	session.Track(now.Add(3*time.Second), "h", "hi", "hi")
+
+	keystrokes := session.Keystrokes()
+	if len(keystrokes) != 4 {
+		t.Fatalf("expected 4 keystrokes, got %d", len(keystrokes))
+	}
+	if !keystrokes[0].Correct || keystrokes[0].Rune != 'h' || keystrokes[0].Position != 0 {
+		t.Fatalf("unexpected first keystroke: %+v", keystrokes[0])
+	}
+	if keystrokes[1].Correct || keystrokes[1].Position != 1 {
+		t.Fatalf("expected second keystroke to be an error at position 1, got %+v", keystrokes[1])
+	}
+	if !keystrokes[2].Backspace || keystrokes[2].Rune != 'x' {
+		t.Fatalf("expected third keystroke to be a backspace over 'x', got %+v", keystrokes[2])
+	}
+	if !keystrokes[3].Correct || keystrokes[3].Rune != 'i' {
+		t.Fatalf("unexpected final keystroke: %+v", keystrokes[3])
+	}
+}
+
+func TestSessionTrackCollapsesRewrites(t *testing.T) {
+	session := NewSession()
+	now := time.Now()
+	session.Start(now)
+
+	session.Track(now, "", " ", "\tx")
+	session.Track(now, " ", "  ", "\tx")
+	session.Track(now, "  ", "   ", "\tx")
+	session.Track(now, "   ", "\t", "\tx")
+
+	keystrokes := session.Keystrokes()
+	if len(keystrokes) != 1 {
+		t.Fatalf("expected rewritten spaces to collapse into one keystroke, got %d", len(keystrokes))
+	}
+	if !keystrokes[0].Correct || keystrokes[0].Rune != '\t' {
+		t.Fatalf("expected a correct tab keystroke, got %+v", keystrokes[0])
+	}
+}
+
+func TestSessionTrackIgnoredWhenIdle(t *testing.T) {
+	session := NewSession()
+	session.Track(time.Now(), "", "a", "a")
+	if len(session.Keystrokes()) != 0 {
+		t.Fatalf("expected no keystrokes before the session starts")
+	}
+
+	session.Start(time.Now())
+	session.Track(time.Now(), "", "a", "a")
+	session.Reset()
+	if len(session.Keystrokes()) != 0 {
+		t.Fatalf("expected reset to clear keystrokes")
+	}
+}
+
+func TestSessionFinishResults(t *testing.T) {
+	session := NewSession()
+	start := time.Now()
+	session.Start(start)
+	session.Track(start, "", "hx", "hello")
+	session.Track(start, "hx", "h", "hello")
+	session.Track(start, "h", "hel", "hello")
+
+	session.Finish(start.Add(time.Minute), "hel", "hello")
+	results := session.Results()
+
+	expected := CharCounts{Correct: 3, Missed: 2}
+	if results.Chars != expected {
+		t.Fatalf("expected char counts %+v, got %+v", expected, results.Chars)
+	}
+	if math.Abs(results.Accuracy-75) > 0.0001 {
+		t.Fatalf("expected accuracy of 75%%, got %f", results.Accuracy)
+	}
+	if math.Abs(results.RawWPM-0.6) > 0.0001 {
+		t.Fatalf("expected raw WPM of 0.6, got %f", results.RawWPM)
+	}
+	if math.Abs(results.CPM-3) > 0.0001 {
+		t.Fatalf("expected CPM of 3, got %f", results.CPM)
+	}
+}
+
+func TestSessionWordMetric(t *testing.T) {
+	session := NewSessionWithOptions(SessionOptions{Metric: models.WordWPM})
+	start := time.Now()
+	session.Start(start)
+
+	final := session.Finish(start.Add(time.Minute), "hello wrld", "hello world")
+	if math.Abs(final-2) > 0.0001 {
+		t.Fatalf("expected word metric to count both words, got %f", final)
+	}
+
+	reset := NewSessionWithOptions(SessionOptions{Metric: models.WordWPM})
+	reset.Start(start)
+	reset.Reset()
+	if reset.Metric() != models.WordWPM {
+		t.Fatalf("expected reset to keep session options")
+	}
+}
+
+func TestSessionCharacterMetricIgnoresErrors(t *testing.T) {
+	session := NewSession()
+	start := time.Now()
+	session.Start(start)
+
+	current := session.CurrentWPM(start.Add(time.Minute), "hellx", "hello")
+	if math.Abs(current-0.8) > 0.0001 {
+		t.Fatalf("expected only correct characters to count, got %f", current)
+	}
+}
+
+type recorderFunc func(models.Result) error
+
+func (f recorderFunc) Append(result models.Result) error {
+	return f(result)
+}
+
+// save runs the command SaveCmd returns, as a program would, and feeds its
+// message back to the session.
+func save(session *Session) {
+	if cmd := session.SaveCmd(); cmd != nil {
+		session.Saved(cmd().(SavedMsg))
+	}
+}
+
+func TestSessionRecordsResult(t *testing.T) {
+	var recorded []models.Result
+	cfg := models.Config{Mode: models.WordsMode, Language: models.English, WordCount: 10, Duration: 60, IncludeNumbers: true}
+	opts := SessionOptionsFromConfig(cfg)
+	opts.Recorder = recorderFunc(func(result models.Result) error {
+		recorded = append(recorded, result)
+		return nil
+	})
+
+	session := NewSessionWithOptions(opts)
+	start := time.Now()
+	session.Start(start)
+	session.Track(start, "", "hello", "hello")
+	session.Finish(start.Add(time.Minute), "hello", "hello")
+	if len(recorded) != 0 {
+		t.Fatalf("expected the result to be saved by SaveCmd, not by Finish")
+	}
+	save(&session)
+	session.Finish(start.Add(2*time.Minute), "hello", "hello")
+	save(&session)
+
+	if len(recorded) != 1 {
+		t.Fatalf("expected exactly one recorded result, got %d", len(recorded))
+	}
+	result := recorded[0]
+	if result.Mode != models.WordsMode || result.WordCount != 10 || result.Duration != 0 || !result.Numbers {
+		t.Fatalf("expected result to describe the words test, got %+v", result)
+	}
+	if result.Outcome != models.OutcomeCompleted || result.CorrectChars != 5 || result.ElapsedSeconds != 60 {
+		t.Fatalf("unexpected recorded scores: %+v", result)
+	}
+}
+
+func TestSessionAbandonRecordsIncomplete(t *testing.T) {
+	var recorded []models.Result
+	opts := SessionOptions{Recorder: recorderFunc(func(result models.Result) error {
+		recorded = append(recorded, result)
+		return nil
+	})}
+
+	idle := NewSessionWithOptions(opts)
+	idle.Abandon(time.Now(), "", "hello")
+	if idle.SaveCmd() != nil {
+		t.Fatalf("expected a session that never started not to be recorded")
+	}
+
+	session := NewSessionWithOptions(opts)
+	session.Start(time.Now())
+	session.SetQuoteID(42)
+	session.Abandon(time.Now(), "he", "hello")
+	save(&session)
+	if len(recorded) != 1 || recorded[0].Outcome != models.OutcomeIncomplete || recorded[0].QuoteID != 42 {
+		t.Fatalf("expected an incomplete quote result, got %+v", recorded)
+	}
+	if session.Outcome() != models.OutcomeIncomplete {
+		t.Fatalf("expected session outcome to be incomplete")
+	}
+}
+
+func TestSessionPersonalBest(t *testing.T) {
+	session := NewSessionWithOptions(SessionOptions{PersonalBest: 1})
+	start := time.Now()
+
+	// "hello" in one minute is 1 WPM, which only ties the best.
+	session.Start(start)
+	session.Finish(start.Add(time.Minute), "hello", "hello")
+	if _, ok := session.NewPersonalBest(); ok {
+		t.Fatalf("expected a tie not to be a new personal best")
+	}
+
+	session.Reset()
+	session.Start(start)
+	session.Finish(start.Add(30*time.Second), "hello", "hello")
+	previous, ok := session.NewPersonalBest()
+	if !ok || previous != 1 {
+		t.Fatalf("expected new personal best over 1, got %v (%v)", previous, ok)
+	}
+	if session.PersonalBest() != 2 {
+		t.Fatalf("expected personal best to rise to 2, got %v", session.PersonalBest())
+	}
+
+	session.Reset()
+	if _, ok := session.NewPersonalBest(); ok {
+		t.Fatalf("expected reset to clear the new personal best flag")
+	}
+	session.Start(start)
+	session.Abandon(start.Add(10*time.Second), "hello", "hello")
+	if _, ok := session.NewPersonalBest(); ok || session.PersonalBest() != 2 {
+		t.Fatalf("expected incomplete sessions not to set personal bests")
+	}
+}
+
+func TestSessionFirstResultSetsPersonalBestSilently(t *testing.T) {
+	session := NewSessionWithOptions(SessionOptions{})
+	start := time.Now()
+	session.Start(start)
+	session.Finish(start.Add(time.Minute), "hello", "hello")
+
+	if _, ok := session.NewPersonalBest(); ok {
+		t.Fatalf("expected no banner without a previous best")
+	}
+	if session.PersonalBest() != 1 {
+		t.Fatalf("expected first result to become the personal best, got %v", session.PersonalBest())
+	}
+}
+
+func TestFreeSessionCountsEverythingTyped(t *testing.T) {
+	session := NewSessionWithOptions(SessionOptions{Free: true, Config: models.Config{Mode: models.ZenMode}})
+	start := time.Now()
+	session.Start(start)
+	session.Track(start, "", "helo", "")
+	session.Track(start.Add(time.Second), "helo", "hel", "")
+	session.Track(start.Add(2*time.Second), "hel", "hello world", "")
+
+	wpm := session.Finish(start.Add(time.Minute), "hello world", "")
+	if math.Abs(wpm-2.2) > 0.0001 {
+		t.Fatalf("expected every typed character to count, got %f", wpm)
+	}
+	if got := session.KeystrokeCount(); got != 13 {
+		t.Fatalf("expected 13 keystrokes including the backspace, got %d", got)
+	}
+	if results := session.Results(); results.Chars.Extra != 0 || results.Accuracy != 100 {
+		t.Fatalf("expected no errors without a target, got %+v", results.Chars)
+	}
+
+	completion := RenderCompletion(CompletionConfig{Width: 80, Session: &session})
+	if !strings.Contains(completion, "Keystrokes") || strings.Contains(completion, "Accuracy") {
+		t.Fatalf("expected the free results screen, got %q", completion)
+	}
+}
+
+// typeInto feeds the runes that turn from into text to session one at a
+// time, a second apart, as key presses would.
+func typeInto(session *Session, start time.Time, from, text, target string) {
+	runes := []rune(text)
+	for i := len([]rune(from)); i < len(runes); i++ {
+		session.Track(start.Add(time.Duration(i)*time.Second), string(runes[:i]), string(runes[:i+1]), target)
+	}
+}
+
+func TestExpertDifficultyFailsOnSubmittedMistakes(t *testing.T) {
+	var recorded []models.Result
+	opts := SessionOptions{Difficulty: models.ExpertDifficulty, PersonalBest: 1, Recorder: recorderFunc(func(result models.Result) error {
+		recorded = append(recorded, result)
+		return nil
+	})}
+	start := time.Now()
+
+	session := NewSessionWithOptions(opts)
+	session.Start(start)
+	typeInto(&session, start, "", "helo", "hello world")
+	if session.Finished() {
+		t.Fatalf("expected a mistake inside the current word to be allowed")
+	}
+	session.Track(start.Add(5*time.Second), "helo", "hel", "hello world")
+	typeInto(&session, start, "hel", "hello world", "hello world")
+	if session.Finished() {
+		t.Fatalf("expected corrected words to pass")
+	}
+
+	session = NewSessionWithOptions(opts)
+	session.Start(start)
+	typeInto(&session, start, "", "helo wor", "hello world")
+	save(&session)
+	if !session.Finished() || session.Outcome() != models.OutcomeFailed {
+		t.Fatalf("expected submitting a wrong word to fail, got outcome %q", session.Outcome())
+	}
+	if _, ok := session.NewPersonalBest(); ok || session.PersonalBest() != 1 {
+		t.Fatalf("expected failed sessions not to set personal bests")
+	}
+	if len(recorded) != 1 || recorded[0].Outcome != models.OutcomeFailed || recorded[0].Difficulty != models.ExpertDifficulty {
+		t.Fatalf("expected a failed expert result to be recorded, got %+v", recorded)
+	}
+	if session.Elapsed(start.Add(time.Hour)) != 4*time.Second {
+		t.Fatalf("expected the session to end on the failing space, got %s", session.Elapsed(start.Add(time.Hour)))
+	}
+}
+
+func TestExpertDifficultyWordLevel(t *testing.T) {
+	start := time.Now()
+	session := NewSessionWithOptions(SessionOptions{Difficulty: models.ExpertDifficulty, WordLevel: true})
+	session.Start(start)
+	typeInto(&session, start, "", "hel ", "hello world")
+	if session.Outcome() != models.OutcomeFailed {
+		t.Fatalf("expected a partial word submitted with space to fail, got %q", session.Outcome())
+	}
+}
+
+func TestMasterDifficultyFailsOnFirstWrongKey(t *testing.T) {
+	start := time.Now()
+	session := NewSessionWithOptions(SessionOptions{Difficulty: models.MasterDifficulty})
+	session.Start(start)
+	typeInto(&session, start, "", "hel", "hello")
+	if session.Finished() {
+		t.Fatalf("expected correct keys to pass")
+	}
+	session.Track(start.Add(3*time.Second), "hel", "helo", "hello")
+	if session.Outcome() != models.OutcomeFailed {
+		t.Fatalf("expected a wrong key to fail, got %q", session.Outcome())
+	}
+	if session.Result().Difficulty != models.MasterDifficulty {
+		t.Fatalf("expected the result to record the difficulty")
+	}
+	completion := RenderCompletion(CompletionConfig{Width: 80, Session: &session})
+	if !strings.Contains(completion, "Failed after") || !strings.Contains(completion, "master") {
+		t.Fatalf("expected the completion to report the failure, got %q", completion)
+	}
+}
+
+func TestDifficultiesAcceptSpacesForATab(t *testing.T) {
+	target := "if ok {\n\treturn\n}"
+	for _, difficulty := range []models.Difficulty{models.ExpertDifficulty, models.MasterDifficulty} {
+		start := time.Now()
+		session := NewSessionWithOptions(SessionOptions{Difficulty: difficulty})
+		session.Start(start)
+		typeInto(&session, start, "", "if ok {\n   ", target)
+		if session.Finished() {
+			t.Fatalf("%s: expected spaces typed towards a tab to pass", difficulty)
+		}
+		// The text area collapses the fourth space into the tab.
+		session.Track(start.Add(time.Minute), "if ok {\n   ", "if ok {\n\t", target)
+		typeInto(&session, start.Add(time.Minute), "if ok {\n\t", "if ok {\n\treturn", target)
+		if session.Finished() {
+			t.Fatalf("%s: expected the collapsed tab to pass, got %q", difficulty, session.Outcome())
+		}
+	}
+}
+
+func TestNormalDifficultyIsNotRecorded(t *testing.T) {
+	session := NewSessionWithOptions(SessionOptions{})
+	if session.Difficulty() != models.NormalDifficulty || session.Result().Difficulty != "" {
+		t.Fatalf("expected normal difficulty to be left out of results")
+	}
+}
+
+func TestCurrentWPMWordLevelAfterMistakenWord(t *testing.T) {
+	typed, target := "hel world again and again", "hello world again and again"
+	session := NewSessionWithOptions(SessionOptions{WordLevel: true})
+	start := time.Now()
+	session.Start(start)
+
+	live := session.CurrentWPM(start.Add(time.Minute), typed, target)
+	// "hel", "world again and again" and four spaces are correct: 25
+	// characters, or 5 WPM over a minute.
+	if math.Abs(live-5) > 0.0001 {
+		t.Fatalf("expected live WPM of 5 after a partial word, got %f", live)
+	}
+	if final := session.Finish(start.Add(time.Minute), typed, target); math.Abs(final-live) > 0.0001 {
+		t.Fatalf("expected live and final WPM to agree, got %f and %f", live, final)
+	}
+}
+
+func TestSavedIgnoresEarlierTests(t *testing.T) {
+	failure := errors.New("disk full")
+	session := NewSessionWithOptions(SessionOptions{Recorder: recorderFunc(func(models.Result) error {
+		return failure
+	})})
+	start := time.Now()
+	session.Start(start)
+	session.Finish(start.Add(time.Second), "hi", "hi")
+	cmd := session.SaveCmd()
+	if cmd == nil || session.SaveCmd() != nil {
+		t.Fatalf("expected exactly one save command per finished test")
+	}
+	msg := cmd().(SavedMsg)
+	if err := FlushSaves(); err != nil {
+		t.Fatalf("expected nothing left to flush, got %v", err)
+	}
+
+	session.Reset()
+	session.Saved(msg)
+	if session.RecordError() != nil {
+		t.Fatalf("expected a late save of the previous test to be ignored")
+	}
+
+	session.Start(start)
+	session.Finish(start.Add(time.Second), "hi", "hi")
+	save(&session)
+	if !errors.Is(session.RecordError(), failure) {
+		t.Fatalf("expected the save error to be reported, got %v", session.RecordError())
+	}
+}
+
+func TestFlushSavesWritesDroppedCommands(t *testing.T) {
+	var recorded []models.Result
+	session := NewSessionWithOptions(SessionOptions{Recorder: recorderFunc(func(result models.Result) error {
+		recorded = append(recorded, result)
+		return nil
+	})})
+	start := time.Now()
+	session.Start(start)
+	session.Finish(start.Add(time.Second), "hi", "hi")
+
+	// The program quit before running the command.
+	cmd := session.SaveCmd()
+	if err := FlushSaves(); err != nil || len(recorded) != 1 {
+		t.Fatalf("expected the dropped result to be written once, got %d (%v)", len(recorded), err)
+	}
+	if msg := cmd().(SavedMsg); msg.Err != nil || len(recorded) != 1 {
+		t.Fatalf("expected a late command not to write the result again, got %d (%v)", len(recorded), msg.Err)
+	}
+}
@@ -0,0 +1,102 @@
+package typing
+
+import (
+	"math"
+	"time"
+)
+
+// Sample captures typing speed for one second of a session. RawWPM only
+// counts keystrokes made during that second while NetWPM is cumulative up to
+// the end of it. Both come from the keystroke log, so every wrong key counts
+// against NetWPM even once it was corrected; the final scores only count the
+// mistakes left in the text, and the last sample need not match them.
+type Sample struct {
+	Second int
+	RawWPM float64
+	NetWPM float64
+	Errors int
+}
+
+// buildTimeline buckets the keystroke log into one sample per elapsed second.
+// A trailing fraction of a second is folded into the last sample rather than
+// given a bucket of its own, so the last sample spans between half a second
+// and a second and a half.
+func buildTimeline(start time.Time, elapsed time.Duration, keystrokes []Keystroke, opts SessionOptions) []Sample {
+	if elapsed <= 0 {
+		return nil
+	}
+
+	seconds := max(int(math.Round(elapsed.Seconds())), 1)
+	typed := make([]int, seconds)
+	errors := make([]int, seconds)
+	for _, k := range keystrokes {
+		if k.Backspace {
+			continue
+		}
+		index := int(k.Time.Sub(start) / time.Second)
+		if index < 0 {
+			index = 0
+		}
+		if index >= seconds {
+			index = seconds - 1
+		}
+		typed[index]++
+		if !k.Correct {
+			errors[index]++
+		}
+	}
+
+	samples := make([]Sample, seconds)
+	cumulativeTyped, cumulativeErrors := 0, 0
+	for i := range samples {
+		cumulativeTyped += typed[i]
+		cumulativeErrors += errors[i]
+
+		// Raw speed is scaled by the length of the bucket, which only
+		// differs from a second for the last one.
+		end := time.Duration(i+1) * time.Second
+		if i == seconds-1 {
+			end = elapsed
+		}
+		length := end - time.Duration(i)*time.Second
+		raw := float64(typed[i]) / opts.charsPerWord() / length.Minutes()
+		minutes := end.Minutes()
+		cumulativeRaw := float64(cumulativeTyped) / opts.charsPerWord() / minutes
+		net := math.Max(0, cumulativeRaw-float64(cumulativeErrors)/minutes)
+
+		samples[i] = Sample{
+			Second: i + 1,
+			RawWPM: sanitize(raw),
+			NetWPM: sanitize(net),
+			Errors: errors[i],
+		}
+	}
+	return samples
+}
+
+// consistency maps the coefficient of variation of per-second raw speed onto
+// a 0-100 score. The odd-power series keeps small variations near 100 while
+// tanh bounds very erratic sessions above 0, matching monkeytype's scale.
+func consistency(samples []Sample) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+
+	mean := 0.0
+	for _, sample := range samples {
+		mean += sample.RawWPM
+	}
+	mean /= float64(len(samples))
+	if mean <= 0 {
+		return 0
+	}
+
+	variance := 0.0
+	for _, sample := range samples {
+		diff := sample.RawWPM - mean
+		variance += diff * diff
+	}
+	cv := math.Sqrt(variance/float64(len(samples))) / mean
+
+	return sanitize(100 * (1 - math.Tanh(cv+math.Pow(cv, 3)/3+math.Pow(cv, 5)/5)))
+}
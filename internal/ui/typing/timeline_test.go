@@ -0,0 +1,121 @@
+package typing
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neilsmahajan/typing-test-tui/internal/ui/theme"
+)
+
+func TestBuildTimelineBucketsBySecond(t *testing.T) {
+	start := time.Now()
+	keystrokes := []Keystroke{
+		{Rune: 'a', Time: start.Add(100 * time.Millisecond), Correct: true},
+		{Rune: 'b', Time: start.Add(900 * time.Millisecond), Correct: false},
+		{Rune: 'b', Time: start.Add(1100 * time.Millisecond), Backspace: true},
+		{Rune: 'c', Time: start.Add(1500 * time.Millisecond), Correct: true},
+	}
+
+	samples := buildTimeline(start, 2*time.Second, keystrokes, SessionOptions{})
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 samples, got %d", len(samples))
+	}
+	if samples[0].Errors != 1 || samples[1].Errors != 0 {
+		t.Fatalf("unexpected error counts: %+v", samples)
+	}
+	if math.Abs(samples[0].RawWPM-24) > 0.0001 {
+		t.Fatalf("expected 2 keystrokes in a second to be 24 raw WPM, got %f", samples[0].RawWPM)
+	}
+	if math.Abs(samples[1].RawWPM-12) > 0.0001 {
+		t.Fatalf("expected backspaces to be ignored for raw WPM, got %f", samples[1].RawWPM)
+	}
+	if samples[1].NetWPM != 0 {
+		t.Fatalf("expected the uncorrected error to cancel out net WPM, got %f", samples[1].NetWPM)
+	}
+}
+
+func TestSessionFinishBuildsTimeline(t *testing.T) {
+	session := NewSession()
+	start := time.Now()
+	session.Start(start)
+	session.Track(start.Add(500*time.Millisecond), "", "hi", "hi")
+	session.Finish(start.Add(2500*time.Millisecond), "hi", "hi")
+
+	timeline := session.Results().Timeline
+	if len(timeline) != 3 {
+		t.Fatalf("expected a sample for each started second, got %d", len(timeline))
+	}
+	if math.Abs(timeline[2].NetWPM-9.6) > 0.0001 {
+		t.Fatalf("expected net WPM over 2.5s to be 9.6, got %f", timeline[2].NetWPM)
+	}
+}
+
+// steadyKeystrokes types one correct character every 200ms, which is 60 WPM,
+// for as long as elapsed.
+func steadyKeystrokes(start time.Time, elapsed time.Duration) []Keystroke {
+	var keystrokes []Keystroke
+	for at := 100 * time.Millisecond; at < elapsed; at += 200 * time.Millisecond {
+		keystrokes = append(keystrokes, Keystroke{Rune: 'a', Time: start.Add(at), Correct: true})
+	}
+	return keystrokes
+}
+
+func TestBuildTimelineFoldsTrailingFraction(t *testing.T) {
+	// Time tests finish on the tick just after the deadline.
+	start := time.Now()
+	elapsed := 60*time.Second + 50*time.Millisecond
+	samples := buildTimeline(start, elapsed, steadyKeystrokes(start, elapsed), SessionOptions{})
+	if len(samples) != 60 {
+		t.Fatalf("expected the trailing 50ms to join the last second, got %d samples", len(samples))
+	}
+	if last := samples[len(samples)-1]; math.Abs(last.RawWPM-60) > 5 {
+		t.Fatalf("expected the last sample to keep the steady speed, got %f", last.RawWPM)
+	}
+}
+
+func TestRenderChart(t *testing.T) {
+	samples := []Sample{
+		{Second: 1, RawWPM: 40, NetWPM: 40},
+		{Second: 2, RawWPM: 60, NetWPM: 50, Errors: 1},
+		{Second: 3, RawWPM: 50, NetWPM: 50},
+	}
+
+	output := sanitizeANSI(RenderChart(ChartConfig{Width: 40, Styles: theme.DefaultStyles(), Samples: samples}))
+	for _, expected := range []string{"60│", "0│", "×", "1s", "3s", "errors"} {
+		if !strings.Contains(output, expected) {
+			t.Fatalf("expected chart to contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	if RenderChart(ChartConfig{Width: 40, Samples: samples[:1]}) != "" {
+		t.Fatalf("expected no chart for a single sample")
+	}
+}
+
+func TestConsistency(t *testing.T) {
+	steady := []Sample{{RawWPM: 60}, {RawWPM: 60}, {RawWPM: 60}}
+	if got := consistency(steady); math.Abs(got-100) > 0.0001 {
+		t.Fatalf("expected a steady pace to score 100, got %f", got)
+	}
+
+	erratic := []Sample{{RawWPM: 10}, {RawWPM: 110}, {RawWPM: 20}, {RawWPM: 100}}
+	uneven := consistency(erratic)
+	if uneven <= 0 || uneven >= 60 {
+		t.Fatalf("expected an erratic pace to score low, got %f", uneven)
+	}
+
+	if consistency(steady[:1]) != 0 || consistency([]Sample{{}, {}}) != 0 {
+		t.Fatalf("expected too few or idle samples to score zero")
+	}
+}
+
+func TestConsistencyOfTimedFinish(t *testing.T) {
+	start := time.Now()
+	elapsed := 60*time.Second + 50*time.Millisecond
+	samples := buildTimeline(start, elapsed, steadyKeystrokes(start, elapsed), SessionOptions{})
+	if got := consistency(samples); got < 99 {
+		t.Fatalf("expected steady typing finished on a late tick to score about 100, got %f", got)
+	}
+}
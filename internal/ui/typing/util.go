@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/neilsmahajan/typing-test-tui/internal/models"
+	"github.com/rivo/uniseg"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -29,6 +30,19 @@ func FormatDuration(d time.Duration) string {
 	return fmt.Sprintf("%02d:%02d", minutes, remaining)
 }
 
+// splitGraphemes breaks text into user-perceived characters.
+func splitGraphemes(text string) []string {
+	if text == "" {
+		return nil
+	}
+	clusters := make([]string, 0, len(text))
+	graphemes := uniseg.NewGraphemes(text)
+	for graphemes.Next() {
+		clusters = append(clusters, graphemes.Str())
+	}
+	return clusters
+}
+
 func WordCount(text string) int {
 	return len(strings.Fields(text))
 }
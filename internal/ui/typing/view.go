@@ -8,6 +8,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
 	"github.com/neilsmahajan/typing-test-tui/internal/ui/theme"
 )
 
@@ -24,6 +25,8 @@ type BoxConfig struct {
 	Metrics          BoxMetrics
 	ViewportWidth    int
 	NewlineIndicator string
+	// WordLevel renders the target word by word (see EvaluateWords).
+	WordLevel bool
 }
 
 type StatsConfig struct {
@@ -77,11 +80,25 @@ func RenderBox(cfg BoxConfig) string {
 		metrics = ComputeBoxMetrics(cfg.Target, cfg.Styles, cfg.ViewportWidth)
 	}
 
+	complete := renderCharacters(cfg)
+	if cfg.WordLevel {
+		complete = renderWords(cfg)
+	}
+
+	innerWidth := metrics.ContentWidth
+	wrapped := cfg.Styles.QuoteContent.Width(innerWidth).Render(complete)
+	return cfg.Styles.QuoteBox.Width(metrics.OuterWidth).Render(wrapped)
+}
+
+// renderCharacters highlights the target from the first mistake onwards.
+func renderCharacters(cfg BoxConfig) string {
 	indicator := cfg.NewlineIndicator
 	skipIndicatorAfterCursor := false
 
-	target := cfg.Target
-	typed := cfg.Typed
+	// Compare grapheme clusters rather than bytes so accented letters, Hanzi
+	// and combining sequences line up with what was actually typed.
+	target := splitGraphemes(cfg.Target)
+	typed := splitGraphemes(cfg.Typed)
 	typedLen := len(typed)
 	targetLen := len(target)
 	limit := typedLen
@@ -97,50 +114,147 @@ func RenderBox(cfg BoxConfig) string {
 		}
 	}
 
-	correctSegment := target[:incorrectIndex]
-	incorrectSegment := ""
-	if incorrectIndex < limit {
-		incorrectSegment = target[incorrectIndex:limit]
-	}
+	correctSegment := strings.Join(target[:incorrectIndex], "")
+	incorrectSegment := strings.Join(target[incorrectIndex:limit], "")
 
 	complete := renderInlineWithIndicator(cfg.Styles.Typed, correctSegment, indicator) + renderInlineWithIndicator(cfg.Styles.Incorrect, MakeSpacesVisible(incorrectSegment), indicator)
 
 	if typedLen > targetLen {
-		extra := typed[targetLen:]
+		extra := strings.Join(typed[targetLen:], "")
 		if extra != "" {
 			complete += renderInlineWithIndicator(cfg.Styles.Incorrect, MakeSpacesVisible(extra), indicator)
 		}
 	}
 
-	remainingAfterCursor := ""
+	var remaining []string
 	if typedLen < targetLen {
-		remainingAfterCursor = target[typedLen:]
+		remaining = target[typedLen:]
 	}
 
 	if cfg.Session != nil && !cfg.Session.Finished() {
 		cursorGlyph := " "
-		if len(remainingAfterCursor) > 0 {
-			r, size := utf8.DecodeRuneInString(remainingAfterCursor)
-			if size > 0 {
-				if r == utf8.RuneError {
-					cursorGlyph = remainingAfterCursor[:size]
-					remainingAfterCursor = remainingAfterCursor[size:]
-				} else if r == '\n' && indicator != "" {
-					cursorGlyph = indicator
-					skipIndicatorAfterCursor = true
-				} else {
-					cursorGlyph = string(r)
-					remainingAfterCursor = remainingAfterCursor[size:]
-				}
+		if len(remaining) > 0 {
+			if remaining[0] == "\n" && indicator != "" {
+				cursorGlyph = indicator
+				skipIndicatorAfterCursor = true
+			} else {
+				cursorGlyph = remaining[0]
+				remaining = remaining[1:]
 			}
 		}
 		complete += cfg.Styles.Cursor.Render(cursorGlyph)
 	}
 
+	remainingAfterCursor := strings.Join(remaining, "")
 	complete += renderInlineWithIndicatorSkip(cfg.Styles.Remaining, remainingAfterCursor, indicator, skipIndicatorAfterCursor)
-	innerWidth := metrics.ContentWidth
-	wrapped := cfg.Styles.QuoteContent.Width(innerWidth).Render(complete)
-	return cfg.Styles.QuoteBox.Width(metrics.OuterWidth).Render(wrapped)
+	return complete
+}
+
+// renderWords highlights each word independently: submitted words show
+// their wrong and missed letters, the active word carries the cursor and
+// pending words are dimmed.
+func renderWords(cfg BoxConfig) string {
+	words := EvaluateWords(cfg.Typed, cfg.Target)
+	showCursor := cfg.Session != nil && !cfg.Session.Finished()
+	out := styledWriter{styles: cfg.Styles}
+
+	for i, word := range words {
+		target := splitGraphemes(word.Target)
+		typed := splitGraphemes(word.Typed)
+
+		if word.State == WordPending {
+			out.write(segmentRemaining, word.Target)
+		} else {
+			for j, cluster := range target {
+				switch {
+				case j < len(typed) && typed[j] == cluster:
+					out.write(segmentTyped, cluster)
+				case j < len(typed):
+					out.write(segmentIncorrect, cluster)
+				case word.Submitted():
+					out.write(segmentMissed, cluster)
+				case showCursor && j == len(typed):
+					out.cursor(cluster)
+				default:
+					out.write(segmentRemaining, cluster)
+				}
+			}
+			if len(typed) > len(target) {
+				out.write(segmentIncorrect, strings.Join(typed[len(target):], ""))
+			}
+		}
+
+		// The cursor waits on the separating space once the active word has
+		// been typed out in full.
+		atSpace := showCursor && word.State == WordActive && len(typed) >= len(target)
+		switch {
+		case atSpace:
+			out.cursor(" ")
+		case i == len(words)-1:
+		case words[i+1].State != WordPending:
+			out.write(segmentTyped, " ")
+		default:
+			out.write(segmentRemaining, " ")
+		}
+	}
+
+	return out.String()
+}
+
+type segmentKind int
+
+const (
+	segmentTyped segmentKind = iota
+	segmentIncorrect
+	segmentMissed
+	segmentRemaining
+)
+
+// styledWriter batches consecutive text of the same kind into a single render.
+type styledWriter struct {
+	styles  theme.Styles
+	builder strings.Builder
+	pending strings.Builder
+	kind    segmentKind
+}
+
+func (w *styledWriter) write(kind segmentKind, text string) {
+	if w.pending.Len() > 0 && w.kind != kind {
+		w.flush()
+	}
+	w.kind = kind
+	w.pending.WriteString(text)
+}
+
+func (w *styledWriter) cursor(glyph string) {
+	w.flush()
+	w.builder.WriteString(w.styles.Cursor.Render(glyph))
+}
+
+func (w *styledWriter) flush() {
+	if w.pending.Len() == 0 {
+		return
+	}
+	w.builder.WriteString(w.style().Render(w.pending.String()))
+	w.pending.Reset()
+}
+
+func (w *styledWriter) style() lipgloss.Style {
+	switch w.kind {
+	case segmentTyped:
+		return w.styles.Typed
+	case segmentIncorrect:
+		return w.styles.Incorrect
+	case segmentMissed:
+		return w.styles.Missed
+	default:
+		return w.styles.Remaining
+	}
+}
+
+func (w *styledWriter) String() string {
+	w.flush()
+	return w.builder.String()
 }
 
 func RenderStats(cfg StatsConfig) string {
@@ -174,7 +288,7 @@ func RenderStats(cfg StatsConfig) string {
 	}
 	wpmLabel := cfg.WPMLabel
 	if wpmLabel == "" {
-		wpmLabel = "WPM"
+		wpmLabel = cfg.Session.Unit()
 	}
 	timeLabel := cfg.TimeLabel
 	if timeLabel == "" {
@@ -184,7 +298,7 @@ func RenderStats(cfg StatsConfig) string {
 	wpmValue := "--"
 	if cfg.WPMValue != "" {
 		wpmValue = cfg.WPMValue
-	} else if wpm := cfg.Session.CurrentWPM(now, cfg.Typed); wpm > 0 {
+	} else if wpm := cfg.Session.CurrentWPM(now, cfg.Typed, cfg.Target); wpm > 0 {
 		wpmValue = fmt.Sprintf("%.1f", wpm)
 	}
 
@@ -199,12 +313,7 @@ func RenderStats(cfg StatsConfig) string {
 		renderStatBlock(cfg.Styles, timeLabel, elapsedValue),
 	}
 
-	row := statEntries[0]
-	for i := 1; i < len(statEntries); i++ {
-		row = lipgloss.JoinHorizontal(lipgloss.Left, row, cfg.Styles.StatSeparator, statEntries[i])
-	}
-
-	return cfg.Styles.StatsRow.MaxWidth(cfg.Width).Render(row)
+	return renderStatRow(cfg.Styles, cfg.Width, statEntries)
 }
 
 func RenderCompletion(cfg CompletionConfig) string {
@@ -218,20 +327,138 @@ func RenderCompletion(cfg CompletionConfig) string {
 	}
 
 	duration := cfg.Session.Elapsed(now)
-	summary := fmt.Sprintf("✅ Completed in %s · WPM %.2f", FormatDuration(duration), cfg.Session.WPM())
+	results := cfg.Session.Results()
+	unit := cfg.Session.Unit()
+	summary := fmt.Sprintf("✅ Completed in %s · %s %.2f", FormatDuration(duration), unit, cfg.Session.WPM())
+	if cfg.Session.Free() {
+		summary = fmt.Sprintf("✅ Finished after %s · %s %.2f", FormatDuration(duration), unit, cfg.Session.WPM())
+	}
+	summaryStyle := cfg.Styles.Success
+	switch cfg.Session.Outcome() {
+	case models.OutcomeIncomplete:
+		summary = fmt.Sprintf("⏹ Ended early after %s · %s %.2f", FormatDuration(duration), unit, cfg.Session.WPM())
+	case models.OutcomeFailed:
+		summary = fmt.Sprintf("❌ Failed after %s · %s", FormatDuration(duration), failureReason(cfg.Session.Difficulty()))
+		summaryStyle = cfg.Styles.Failure
+	}
 	prompt := cfg.Prompt
 	if prompt == "" {
 		prompt = "Press enter to continue, or Ctrl+C to exit."
 	}
 
 	lines := []string{
-		cfg.Styles.Success.MaxWidth(cfg.Width).Render(summary),
-		cfg.Styles.Instruction.MaxWidth(cfg.Width).Render(prompt),
+		summaryStyle.MaxWidth(cfg.Width).Render(summary),
+	}
+	if previous, ok := cfg.Session.NewPersonalBest(); ok {
+		banner := fmt.Sprintf("🏆 New personal best! %.1f %s (previous %.1f)", cfg.Session.WPM(), unit, previous)
+		lines = append(lines, cfg.Styles.PersonalBest.MaxWidth(cfg.Width).Render(banner))
+	}
+	if cfg.Session.Free() {
+		lines = append(lines, renderFreeScores(cfg, duration)...)
+	} else {
+		lines = append(lines, renderScores(cfg)...)
+	}
+
+	if chart := RenderChart(ChartConfig{
+		Width:   cfg.Width,
+		Styles:  cfg.Styles,
+		Samples: results.Timeline,
+		Unit:    unit,
+	}); chart != "" {
+		lines = append(lines, chart)
 	}
 
+	if err := cfg.Session.RecordError(); err != nil {
+		lines = append(lines, cfg.Styles.Incorrect.UnsetUnderline().MarginTop(1).MaxWidth(cfg.Width).Render("Could not save result: "+err.Error()))
+	}
+
+	lines = append(lines, cfg.Styles.Instruction.MaxWidth(cfg.Width).Render(prompt))
+
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
 
+// failureReason explains which rule of the difficulty ended a failed session.
+func failureReason(difficulty models.Difficulty) string {
+	if difficulty == models.MasterDifficulty {
+		return "master difficulty allows no wrong keystrokes"
+	}
+	return "expert difficulty allows no words submitted with mistakes"
+}
+
+// renderScores lists the speed, accuracy and character breakdown of a
+// session typed against a target.
+func renderScores(cfg CompletionConfig) []string {
+	results := cfg.Session.Results()
+	unit := cfg.Session.Unit()
+
+	scoreEntries := []string{
+		renderStatBlock(cfg.Styles, unit, fmt.Sprintf("%.1f", cfg.Session.WPM())),
+		renderStatBlock(cfg.Styles, "Accuracy", fmt.Sprintf("%.1f%%", results.Accuracy)),
+		renderStatBlock(cfg.Styles, "Consistency", formatConsistency(results)),
+	}
+	speedEntries := []string{
+		renderStatBlock(cfg.Styles, "Raw "+unit, fmt.Sprintf("%.1f", results.RawWPM)),
+		renderStatBlock(cfg.Styles, "Net "+unit, fmt.Sprintf("%.1f", results.NetWPM)),
+	}
+	// Character-per-minute scoring already reports CPM as its headline speed.
+	if unit != "CPM" {
+		speedEntries = append(speedEntries, renderStatBlock(cfg.Styles, "CPM", fmt.Sprintf("%.0f", results.CPM)))
+	}
+
+	chars := fmt.Sprintf(
+		"Characters: %d correct · %d incorrect · %d extra · %d missed",
+		results.Chars.Correct,
+		results.Chars.Incorrect,
+		results.Chars.Extra,
+		results.Chars.Missed,
+	)
+
+	lines := []string{
+		renderStatRow(cfg.Styles, cfg.Width, scoreEntries),
+		renderStatRow(cfg.Styles, cfg.Width, speedEntries),
+		cfg.Styles.Subtitle.MaxWidth(cfg.Width).Render(chars),
+	}
+
+	if words := results.Words; words.Correct+words.Errors() > 0 {
+		summary := fmt.Sprintf("Words: %d correct · %d incorrect · %d partial", words.Correct, words.Incorrect, words.Partial)
+		lines = append(lines, cfg.Styles.Subtitle.MaxWidth(cfg.Width).Render(summary))
+	}
+
+	return lines
+}
+
+// renderFreeScores reports a session without a target, where accuracy and
+// character errors mean nothing: only speed, keystrokes and time.
+func renderFreeScores(cfg CompletionConfig, duration time.Duration) []string {
+	results := cfg.Session.Results()
+	entries := []string{
+		renderStatBlock(cfg.Styles, cfg.Session.Unit(), fmt.Sprintf("%.1f", cfg.Session.WPM())),
+		renderStatBlock(cfg.Styles, "Keystrokes", fmt.Sprintf("%d", cfg.Session.KeystrokeCount())),
+		renderStatBlock(cfg.Styles, "Time", FormatDuration(duration)),
+	}
+	chars := fmt.Sprintf("Characters: %d", results.Chars.Correct)
+	return []string{
+		renderStatRow(cfg.Styles, cfg.Width, entries),
+		cfg.Styles.Subtitle.MaxWidth(cfg.Width).Render(chars),
+	}
+}
+
+// PersonalBestLabel describes the session's personal best for subtitles, or
+// returns an empty string when there is none yet.
+func PersonalBestLabel(session *Session) string {
+	if session == nil || session.PersonalBest() <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("PB: %.1f %s", session.PersonalBest(), session.Unit())
+}
+
+func formatConsistency(results Results) string {
+	if len(results.Timeline) < 2 {
+		return "--"
+	}
+	return fmt.Sprintf("%.0f%%", results.Consistency)
+}
+
 func RenderInstructions(cfg InstructionsConfig) string {
 	message := cfg.Message
 	if message == "" {
@@ -263,6 +490,17 @@ func computeOuterWidth(target string, viewportWidth int, frame int) int {
 	return outer
 }
 
+func renderStatRow(styles theme.Styles, width int, entries []string) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	row := entries[0]
+	for i := 1; i < len(entries); i++ {
+		row = lipgloss.JoinHorizontal(lipgloss.Left, row, styles.StatSeparator, entries[i])
+	}
+	return styles.StatsRow.MaxWidth(width).Render(row)
+}
+
 func renderStatBlock(styles theme.Styles, label, value string) string {
 	block := lipgloss.JoinVertical(
 		lipgloss.Left,
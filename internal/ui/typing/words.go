@@ -0,0 +1,197 @@
+package typing
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// WordState describes how far a target word has progressed.
+type WordState int
+
+const (
+	// WordPending words have not been reached yet.
+	WordPending WordState = iota
+	// WordActive is the word currently under the cursor.
+	WordActive
+	// WordCorrect words were submitted exactly as written.
+	WordCorrect
+	// WordIncorrect words were submitted with at least one wrong character.
+	WordIncorrect
+	// WordPartial words were submitted early with only a correct prefix typed.
+	WordPartial
+)
+
+// WordResult pairs a target word with what was typed for it.
+type WordResult struct {
+	Target string
+	Typed  string
+	State  WordState
+}
+
+// Submitted reports whether the word was finished with a space.
+func (w WordResult) Submitted() bool {
+	return w.State == WordCorrect || w.State == WordIncorrect || w.State == WordPartial
+}
+
+// WordCounts tallies submitted words by outcome.
+type WordCounts struct {
+	Correct   int
+	Incorrect int
+	Partial   int
+}
+
+// Errors returns the number of submitted words that were not typed exactly.
+func (w WordCounts) Errors() int {
+	return w.Incorrect + w.Partial
+}
+
+// EvaluateWords aligns typed with target word by word. Pressing space submits
+// the current word and moves on to the next one whether or not it matched,
+// so each typed word is compared against the target word at the same index.
+func EvaluateWords(typed, target string) []WordResult {
+	targetWords := strings.Fields(target)
+	typedWords := strings.Split(typed, " ")
+
+	results := make([]WordResult, len(targetWords))
+	for i, word := range targetWords {
+		results[i] = WordResult{Target: word, State: WordPending}
+		if i >= len(typedWords) {
+			continue
+		}
+		results[i].Typed = typedWords[i]
+		if i == len(typedWords)-1 {
+			results[i].State = WordActive
+			continue
+		}
+		results[i].State = submittedState(typedWords[i], word)
+	}
+	return results
+}
+
+// WordsComplete reports whether the last target word has been submitted or
+// typed exactly, which is when a word-based test ends.
+func WordsComplete(typed, target string) bool {
+	targetWords := strings.Fields(target)
+	if len(targetWords) == 0 {
+		return false
+	}
+	typedWords := strings.Split(typed, " ")
+	if len(typedWords) > len(targetWords) {
+		return true
+	}
+	return len(typedWords) == len(targetWords) && typedWords[len(typedWords)-1] == targetWords[len(targetWords)-1]
+}
+
+// IgnoreWordKey reports whether a key press should be dropped in word-based
+// modes: space cannot submit an empty word and enter never inserts a newline.
+func IgnoreWordKey(msg tea.KeyMsg, typed string) bool {
+	switch msg.Type {
+	case tea.KeyEnter:
+		return true
+	case tea.KeySpace:
+		return typed == "" || strings.HasSuffix(typed, " ")
+	}
+	return false
+}
+
+// finalizeWords marks a last word that was typed exactly as correct, since
+// the test ends on it without a trailing space.
+func finalizeWords(words []WordResult) {
+	if len(words) == 0 {
+		return
+	}
+	last := &words[len(words)-1]
+	if last.State == WordActive && last.Typed == last.Target {
+		last.State = WordCorrect
+	}
+}
+
+func submittedState(typed, target string) WordState {
+	switch {
+	case typed == target:
+		return WordCorrect
+	case typed != "" && strings.HasPrefix(target, typed):
+		return WordPartial
+	default:
+		return WordIncorrect
+	}
+}
+
+func countWords(words []WordResult) WordCounts {
+	var counts WordCounts
+	for _, word := range words {
+		switch word.State {
+		case WordCorrect:
+			counts.Correct++
+		case WordIncorrect:
+			counts.Incorrect++
+		case WordPartial:
+			counts.Partial++
+		}
+	}
+	return counts
+}
+
+// countWordChars classifies characters word by word. Submitted words report
+// untyped letters as missed and count their trailing space as correct; the
+// active word only contributes what has been typed so far.
+func countWordChars(words []WordResult) CharCounts {
+	var counts CharCounts
+	for index, word := range words {
+		if word.State == WordPending {
+			continue
+		}
+		typed := []rune(word.Typed)
+		target := []rune(word.Target)
+		for i, r := range typed {
+			switch {
+			case i >= len(target):
+				counts.Extra++
+			case target[i] == r:
+				counts.Correct++
+			default:
+				counts.Incorrect++
+			}
+		}
+		if word.Submitted() {
+			if len(target) > len(typed) {
+				counts.Missed += len(target) - len(typed)
+			}
+			if index < len(words)-1 {
+				counts.Correct++
+			}
+		}
+	}
+	return counts
+}
+
+// wordKeystroke checks the rune at index in typed against the target word it
+// belongs to, returning the matching rune index in target.
+func wordKeystroke(typed []rune, index int, target string) (int, bool) {
+	wordStart := 0
+	wordIndex := 0
+	for i := 0; i < index; i++ {
+		if typed[i] == ' ' {
+			wordIndex++
+			wordStart = i + 1
+		}
+	}
+	offset := index - wordStart
+
+	targetWords := strings.Fields(target)
+	position := 0
+	for i := 0; i < wordIndex && i < len(targetWords); i++ {
+		position += len([]rune(targetWords[i])) + 1
+	}
+	position += offset
+
+	if wordIndex >= len(targetWords) {
+		return position, false
+	}
+	targetWord := []rune(targetWords[wordIndex])
+	if typed[index] == ' ' {
+		return position, string(typed[wordStart:index]) == string(targetWord)
+	}
+	return position, offset < len(targetWord) && targetWord[offset] == typed[index]
+}
@@ -0,0 +1,124 @@
+package typing
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/neilsmahajan/typing-test-tui/internal/ui/theme"
+)
+
+func TestEvaluateWordsStates(t *testing.T) {
+	words := EvaluateWords("the qiuck br fo", "the quick brown fox jumps")
+
+	expected := []WordState{WordCorrect, WordIncorrect, WordPartial, WordActive, WordPending}
+	if len(words) != len(expected) {
+		t.Fatalf("expected %d words, got %d", len(expected), len(words))
+	}
+	for i, state := range expected {
+		if words[i].State != state {
+			t.Fatalf("expected word %d (%q) to have state %d, got %d", i, words[i].Target, state, words[i].State)
+		}
+	}
+	if words[3].Typed != "fo" {
+		t.Fatalf("expected active word to carry typed text, got %q", words[3].Typed)
+	}
+}
+
+func TestWordsComplete(t *testing.T) {
+	target := "one two"
+	cases := map[string]bool{
+		"":         false,
+		"one":      false,
+		"one tw":   false,
+		"one two":  true,
+		"one tx ":  true,
+		"oen tow":  false,
+		"oen two":  true,
+		"one two ": true,
+	}
+	for typed, expected := range cases {
+		if got := WordsComplete(typed, target); got != expected {
+			t.Fatalf("WordsComplete(%q) = %v, expected %v", typed, got, expected)
+		}
+	}
+}
+
+func TestIgnoreWordKey(t *testing.T) {
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	if !IgnoreWordKey(space, "") || !IgnoreWordKey(space, "word ") {
+		t.Fatalf("expected space on an empty word to be ignored")
+	}
+	if IgnoreWordKey(space, "wor") {
+		t.Fatalf("expected space to submit a partially typed word")
+	}
+	if !IgnoreWordKey(tea.KeyMsg{Type: tea.KeyEnter}, "word") {
+		t.Fatalf("expected enter to be ignored")
+	}
+}
+
+func TestWordLevelResults(t *testing.T) {
+	session := NewSessionWithOptions(SessionOptions{WordLevel: true})
+	start := time.Now()
+	session.Start(start)
+	session.Track(start, "", "teh cat", "the cat")
+	session.Finish(start.Add(time.Minute), "teh cat", "the cat")
+
+	results := session.Results()
+	if results.Words != (WordCounts{Correct: 1, Incorrect: 1}) {
+		t.Fatalf("unexpected word counts: %+v", results.Words)
+	}
+	expected := CharCounts{Correct: 5, Incorrect: 2}
+	if results.Chars != expected {
+		t.Fatalf("expected char counts %+v, got %+v", expected, results.Chars)
+	}
+
+	keystrokes := session.Keystrokes()
+	if keystrokes[3].Rune != ' ' || keystrokes[3].Correct {
+		t.Fatalf("expected submitting a wrong word to be an error, got %+v", keystrokes[3])
+	}
+	if !keystrokes[4].Correct || keystrokes[4].Position != 4 {
+		t.Fatalf("expected next word to be checked against its own target, got %+v", keystrokes[4])
+	}
+}
+
+func TestWordLevelSkippedWordCountsMissed(t *testing.T) {
+	results := computeResults(time.Minute, "th cat", "the cat", nil, SessionOptions{WordLevel: true})
+	if results.Chars.Missed != 1 || results.Words.Partial != 1 {
+		t.Fatalf("expected skipped letter to be missed, got %+v", results)
+	}
+}
+
+func TestRenderWordsMarksEachWord(t *testing.T) {
+	styles := theme.DefaultStyles()
+	styles.Incorrect = styles.Incorrect.Transform(func(s string) string { return "[" + s + "]" })
+	styles.Missed = styles.Missed.Transform(func(s string) string { return "{" + s + "}" })
+	session := NewSession()
+
+	output := RenderBox(BoxConfig{
+		Target:    "the quick brown",
+		Typed:     "thx qu",
+		Styles:    styles,
+		Session:   &session,
+		Metrics:   ComputeBoxMetrics("the quick brown", styles, 60),
+		WordLevel: true,
+	})
+
+	cleaned := sanitizeANSI(output)
+	if !strings.Contains(cleaned, "th[e] qu") {
+		t.Fatalf("expected only the wrong letter of the first word to be marked, got:\n%s", cleaned)
+	}
+
+	output = RenderBox(BoxConfig{
+		Target:    "the quick",
+		Typed:     "t quick",
+		Styles:    styles,
+		Session:   &session,
+		Metrics:   ComputeBoxMetrics("the quick", styles, 60),
+		WordLevel: true,
+	})
+	if cleaned := sanitizeANSI(output); !strings.Contains(cleaned, "t{he} quick") {
+		t.Fatalf("expected skipped letters to be marked missed, got:\n%s", cleaned)
+	}
+}
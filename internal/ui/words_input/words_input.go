@@ -30,9 +30,10 @@ type Model struct {
 	session            typing.Session
 }
 
-func InitialModel(languageWords models.LanguageWords, wordCount models.WordCount, includePunctuation bool, includeNumbers bool) Model {
+func InitialModel(languageWords models.LanguageWords, wordCount models.WordCount, includePunctuation bool, includeNumbers bool, sessionOptions typing.SessionOptions) Model {
 	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 	target := generateTargetWords(rng, languageWords, wordCount, includeNumbers, includePunctuation)
+	sessionOptions.WordLevel = true
 
 	ti := textarea.New()
 	ti.Placeholder = target
@@ -48,7 +49,7 @@ func InitialModel(languageWords models.LanguageWords, wordCount models.WordCount
 		includeNumbers:     includeNumbers,
 		rng:                rng,
 		styles:             theme.DefaultStyles(),
-		session:            typing.NewSession(),
+		session:            typing.NewSessionWithOptions(sessionOptions),
 	}
 }
 
@@ -91,9 +92,18 @@ func (m Model) Init() tea.Cmd {
 
 // Update handles messages (key presses, etc.)
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+	return m, tea.Batch(cmd, m.session.SaveCmd())
+}
+
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmd tea.Cmd
+	prevValue := m.currentText.Value()
 
 	switch msg := msg.(type) {
+	case typing.SavedMsg:
+		m.session.Saved(msg)
+		return m, nil
 	case tea.WindowSizeMsg:
 		m.viewportWidth = msg.Width
 		metrics := typing.ComputeBoxMetrics(m.Target, m.styles, m.viewportWidth)
@@ -125,10 +135,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case tea.KeyTab:
 			if !m.session.Finished() {
-				m.session.Finish(time.Now(), m.currentText.Value())
+				m.session.Abandon(time.Now(), m.currentText.Value(), m.Target)
 			}
 			return m, nil
 		}
+
+		if typing.IgnoreWordKey(msg, m.currentText.Value()) {
+			return m, nil
+		}
+		if m.session.Rejects(time.Now(), msg, m.currentText.Value(), m.Target) {
+			return m, nil
+		}
 	case error:
 		return m, nil
 	}
@@ -139,13 +156,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	m.currentText, cmd = m.currentText.Update(msg)
 
+	now := time.Now()
 	if !m.session.Started() && m.currentText.Value() != "" {
-		m.session.Start(time.Now())
+		m.session.Start(now)
 	}
+	m.session.Track(now, prevValue, m.currentText.Value(), m.Target)
 
 	// check if completed (capture finish time & wpm only once)
-	if !m.session.Finished() && m.currentText.Value() == m.Target {
-		m.session.Finish(time.Now(), m.Target)
+	if !m.session.Finished() && typing.WordsComplete(m.currentText.Value(), m.Target) {
+		m.session.Finish(now, m.currentText.Value(), m.Target)
 	}
 
 	return m, cmd
@@ -167,6 +186,7 @@ func (m Model) View() string {
 			Session:       &m.session,
 			Metrics:       metrics,
 			ViewportWidth: m.viewportWidth,
+			WordLevel:     true,
 		}),
 		typing.RenderStats(typing.StatsConfig{
 			Target:  m.Target,
@@ -205,5 +225,8 @@ func (m Model) renderSubtitle(width int) string {
 	languageName := typing.DisplayLanguage(m.languageWords.Language)
 	chars := utf8.RuneCountInString(m.Target)
 	info := fmt.Sprintf("Language: %s · target %d words · %d chars", languageName, m.wordCount, chars)
+	if best := typing.PersonalBestLabel(&m.session); best != "" {
+		info += " · " + best
+	}
 	return m.styles.Subtitle.MaxWidth(width).Render(info)
 }
@@ -0,0 +1,204 @@
+package zen_input
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+	"github.com/neilsmahajan/typing-test-tui/internal/ui/theme"
+	"github.com/neilsmahajan/typing-test-tui/internal/ui/typing"
+)
+
+const (
+	placeholder         = "Start typing…"
+	instructionsMessage = "Tab: finish • Enter: new line • Ctrl+C: exit"
+)
+
+// Model is a free typing session: there is no target text, and the test
+// runs until the user presses Tab.
+type Model struct {
+	// what user has currentText so far
+	currentText   textarea.Model
+	language      models.Language
+	viewportWidth int
+	styles        theme.Styles
+	session       typing.Session
+}
+
+func InitialModel(language models.Language, sessionOptions typing.SessionOptions) Model {
+	sessionOptions.Free = true
+	styles := theme.DefaultStyles()
+	session := typing.NewSessionWithOptions(sessionOptions)
+
+	ti := textarea.New()
+	ti.Placeholder = placeholder
+	ti.SetWidth(typing.DefaultBoxWidth)
+	ti.MaxHeight = 0
+	ti.Focus()
+
+	return Model{
+		currentText: ti,
+		language:    language,
+		styles:      styles,
+		session:     session,
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return textarea.Blink
+}
+
+// Update handles messages (key presses, etc.)
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+	return m, tea.Batch(cmd, m.session.SaveCmd())
+}
+
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	prevValue := m.currentText.Value()
+
+	switch msg := msg.(type) {
+	case typing.SavedMsg:
+		m.session.Saved(msg)
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.viewportWidth = msg.Width
+		metrics := typing.ComputeBoxMetrics("", m.styles, m.viewportWidth)
+		m.currentText.SetWidth(metrics.ContentWidth)
+		return m, nil
+	case tea.KeyMsg:
+		if m.session.Finished() {
+			switch msg.Type {
+			case tea.KeyCtrlC:
+				return m, tea.Quit
+			case tea.KeyEnter:
+				m.session.Reset()
+				m.currentText.SetValue("")
+			}
+			return m, nil
+		}
+
+		switch msg.Type {
+		case tea.KeyCtrlC:
+			return m, tea.Quit
+		case tea.KeyTab:
+			// Finishing before typing anything simply keeps waiting.
+			if m.session.Started() {
+				m.session.Finish(time.Now(), m.currentText.Value(), "")
+			}
+			return m, nil
+		}
+
+		if m.session.Rejects(time.Now(), msg, m.currentText.Value(), "") {
+			return m, nil
+		}
+	case error:
+		return m, nil
+	}
+
+	if !m.currentText.Focused() {
+		m.currentText.Focus()
+	}
+
+	updated, cmd := m.currentText.Update(msg)
+	m.currentText = updated
+
+	if cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+
+	now := time.Now()
+	if !m.session.Started() && m.currentText.Value() != "" {
+		m.session.Start(now)
+	}
+	m.session.Track(now, prevValue, m.currentText.Value(), "")
+
+	return m, tea.Batch(cmds...)
+}
+
+// View defines UI rendering
+func (m Model) View() string {
+	typed := m.currentText.Value()
+	metrics := typing.ComputeBoxMetrics("", m.styles, m.viewportWidth)
+	now := time.Now()
+
+	sections := []string{
+		m.renderHeader(metrics.OuterWidth),
+		m.renderSubtitle(metrics.OuterWidth),
+		m.renderBox(typed, metrics),
+		typing.RenderStats(typing.StatsConfig{
+			Typed:         typed,
+			Width:         metrics.OuterWidth,
+			Styles:        m.styles,
+			Session:       &m.session,
+			Now:           now,
+			ProgressLabel: "Words",
+			ProgressValue: fmt.Sprintf("%d", typing.WordCount(typed)),
+		}),
+	}
+
+	if m.session.Finished() {
+		sections = append(sections, typing.RenderCompletion(typing.CompletionConfig{
+			Width:   metrics.OuterWidth,
+			Styles:  m.styles,
+			Session: &m.session,
+			Now:     now,
+			Prompt:  "Press Enter to start writing again or Ctrl+C to exit.",
+		}))
+	} else {
+		sections = append(sections, typing.RenderInstructions(typing.InstructionsConfig{
+			Width:   metrics.OuterWidth,
+			Styles:  m.styles,
+			Message: instructionsMessage,
+		}))
+	}
+
+	body := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	return "\n" + m.styles.Container.Width(metrics.OuterWidth).Render(body)
+}
+
+func (m Model) renderHeader(width int) string {
+	return m.styles.Header.MaxWidth(width).Render("Zen Mode")
+}
+
+func (m Model) renderSubtitle(width int) string {
+	languageName := typing.DisplayLanguage(m.language)
+	info := fmt.Sprintf("Language: %s · free typing", languageName)
+	if best := typing.PersonalBestLabel(&m.session); best != "" {
+		info += " · " + best
+	}
+	return m.styles.Subtitle.MaxWidth(width).Render(info)
+}
+
+// renderBox shows what has been typed so far with the cursor after it. With
+// no target there is nothing to compare against, so no mistakes are marked.
+func (m Model) renderBox(typed string, metrics typing.BoxMetrics) string {
+	var content string
+	switch {
+	case typed == "" && !m.session.Finished():
+		content = m.styles.Cursor.Render(" ") + m.styles.Remaining.Render(placeholder)
+	case m.session.Finished():
+		content = renderLines(m.styles.Typed, typed)
+	default:
+		content = renderLines(m.styles.Typed, typed) + m.styles.Cursor.Render(" ")
+	}
+
+	wrapped := m.styles.QuoteContent.Width(metrics.ContentWidth).Render(content)
+	return m.styles.QuoteBox.Width(metrics.OuterWidth).Render(wrapped)
+}
+
+// renderLines styles each line separately so line breaks survive rendering.
+func renderLines(style lipgloss.Style, text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = style.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,50 @@
+package zen_input
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/neilsmahajan/typing-test-tui/internal/models"
+	"github.com/neilsmahajan/typing-test-tui/internal/ui/typing"
+)
+
+func typeRunes(m Model, text string) Model {
+	for _, r := range text {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	return m
+}
+
+func TestTabFinishesFreeTyping(t *testing.T) {
+	m := InitialModel(models.English, typing.SessionOptions{})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(Model)
+	if m.session.Finished() {
+		t.Fatal("expected Tab before typing to keep waiting")
+	}
+
+	m = typeRunes(m, "warm up")
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = typeRunes(updated.(Model), "again")
+	if got := m.currentText.Value(); got != "warm up\nagain" {
+		t.Fatalf("expected Enter to start a new line, got %q", got)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(Model)
+	if !m.session.Finished() || m.session.Outcome() != models.OutcomeCompleted {
+		t.Fatalf("expected Tab to finish the session, got outcome %q", m.session.Outcome())
+	}
+	if view := m.View(); !strings.Contains(view, "Keystrokes") {
+		t.Fatalf("expected results with keystrokes, got %q", view)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.session.Started() || m.currentText.Value() != "" {
+		t.Fatal("expected Enter after finishing to start over")
+	}
+}